@@ -1,12 +1,16 @@
 package postgres
 
 import (
+	"strconv"
 	"strings"
 
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/filters"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
 	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 )
 
 /**
@@ -15,7 +19,7 @@ PRIVATE FUNCTIONS
 
 */
 
-func (p *Postgres) prepareInsertFieldset(modelStruct *mapping.ModelStruct, set mapping.FieldSet) (fieldSet mapping.FieldSet, autoSelected mapping.FieldSet) {
+func (p *Postgres) prepareInsertFieldset(modelStruct *mapping.ModelStruct, set mapping.FieldSet, insertDefaults bool) (fieldSet mapping.FieldSet, autoSelected mapping.FieldSet) {
 	// Trim omit keys.
 	for _, field := range set {
 		if field.DatabaseSkip() {
@@ -23,7 +27,15 @@ func (p *Postgres) prepareInsertFieldset(modelStruct *mapping.ModelStruct, set m
 		}
 		fieldSet = append(fieldSet, field)
 	}
-	if p.SelectNotNullsOnInsert {
+	// The CreatedAt and UpdatedAt timestamps are set automatically by the repository.
+	for _, field := range timestampFields(modelStruct) {
+		if fieldSet == nil || !fieldSet.Contains(field) {
+			fieldSet = append(fieldSet, field)
+			autoSelected = append(autoSelected, field)
+		}
+	}
+	// The not null fields doesn't need to be selected if their column defaults are used.
+	if p.SelectNotNullsOnInsert && !insertDefaults {
 		for _, field := range modelStruct.Fields() {
 			if field.Kind() == mapping.KindPrimary {
 				continue
@@ -48,6 +60,10 @@ func (p *Postgres) prepareUpdateModelFieldSet(set mapping.FieldSet) (fieldSet ma
 		if field.Kind() == mapping.KindPrimary {
 			continue
 		}
+		// The version field is incremented by the update query.
+		if migrate.IsVersionField(field) {
+			continue
+		}
 		fieldSet = append(fieldSet, field)
 	}
 
@@ -73,6 +89,133 @@ func (p *Postgres) selectFieldsetNotNulls(s *query.Scope, fieldSet *mapping.Fiel
 	}
 }
 
+// parseModifyFilters parses the filters for the queries that modifies table rows - update and delete.
+// If any of the scope filters were omitted in the strict mode, or all of them were omitted, and the full table
+// query is not allowed, the function returns an error.
+func (p *Postgres) parseModifyFilters(s *query.Scope) (parsedFilters filters.SQLQueries, err error) {
+	if p.StrictFilters {
+		parsedFilters, err = filters.ParseFiltersStrict(s, p.writeQuotedWord)
+	} else {
+		parsedFilters, err = filters.ParseFilters(s, p.writeQuotedWord)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Filters) > 0 && len(parsedFilters) == 0 && !isFullTableAllowed(s) {
+		return nil, errors.WrapDet(filter.ErrFilterField, "all query filters were omitted").
+			WithDetail("Provided filters would result in a full table query. Use AllowFullTable option to allow it.")
+	}
+	return parsedFilters, nil
+}
+
+// timestampFields gets the model's CreatedAt and UpdatedAt fields.
+func timestampFields(mStruct *mapping.ModelStruct) (fields []*mapping.StructField) {
+	if createdAt, ok := mStruct.CreatedAt(); ok && !createdAt.DatabaseSkip() {
+		fields = append(fields, createdAt)
+	}
+	if updatedAt, ok := mStruct.UpdatedAt(); ok && !updatedAt.DatabaseSkip() {
+		fields = append(fields, updatedAt)
+	}
+	return fields
+}
+
+// isTimestampField checks if the 'field' is the model's CreatedAt or UpdatedAt field.
+func isTimestampField(field *mapping.StructField) bool {
+	return field.IsCreatedAt() || field.IsUpdatedAt()
+}
+
+// isAutoTimestamp checks if the timestamp 'field' value needs to be set by the repository. This happens when
+// the field was not selected by the user or its value is zero.
+func isAutoTimestamp(fielder mapping.Fielder, field *mapping.StructField, autoSelected mapping.FieldSet) (bool, error) {
+	if autoSelected != nil && autoSelected.Contains(field) {
+		return true, nil
+	}
+	return fielder.IsFieldZero(field)
+}
+
+// clockValue gets the current time from the repository clock. Returns nil if the clock is not set.
+func (p *Postgres) clockValue() interface{} {
+	if p.Clock == nil {
+		return nil
+	}
+	return p.Clock()
+}
+
+// writeTimestamp writes the automatically set timestamp value. If the 'clockValue' is nil
+// the server side now() function is used, otherwise the 'clockValue' is added as the query argument.
+func (p *Postgres) writeTimestamp(s *query.Scope, sb *strings.Builder, values []interface{}, clockValue interface{}) []interface{} {
+	if clockValue == nil {
+		sb.WriteString("now()")
+		return values
+	}
+	sb.WriteRune('$')
+	sb.WriteString(strconv.Itoa(internal.Incrementor(s)))
+	return append(values, clockValue)
+}
+
+// writeReturning writes the RETURNING clause for provided 'fields'.
+func (p *Postgres) writeReturning(sb *strings.Builder, fields []*mapping.StructField) {
+	if len(fields) == 0 {
+		return
+	}
+	sb.WriteString(" RETURNING ")
+	for i, field := range fields {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		p.writeQuotedWord(sb, field.DatabaseName)
+	}
+}
+
+// writeVersionIncrement writes the increment of the optimistic locking 'version' field i.e.: 'version = version + 1'.
+func (p *Postgres) writeVersionIncrement(sb *strings.Builder, version *mapping.StructField) {
+	p.writeQuotedWord(sb, version.DatabaseName)
+	sb.WriteString(" = ")
+	p.writeQuotedWord(sb, version.DatabaseName)
+	sb.WriteString(" + 1")
+}
+
+// softDeleteFilter gets the 'deleted_at IS NULL' filter for the models with the DeletedAt field.
+// The filter is not added if the scope already filters the DeletedAt field or the IncludeDeleted option is set.
+func (p *Postgres) softDeleteFilter(s *query.Scope) (filters.SQLQuery, bool) {
+	deletedAt, hasDeletedAt := s.ModelStruct.DeletedAt()
+	if !hasDeletedAt || isIncludeDeleted(s) {
+		return filters.SQLQuery{}, false
+	}
+	for _, f := range s.Filters {
+		if isFieldFilter(deletedAt, f) {
+			return filters.SQLQuery{}, false
+		}
+	}
+	sb := &strings.Builder{}
+	p.writeQuotedWord(sb, deletedAt.DatabaseName)
+	sb.WriteString(" IS NULL")
+	return filters.SQLQuery{Query: sb.String()}, true
+}
+
+// updateSoftDeleteFilter gets the soft delete filter for the update of the 'fieldSet'. The update of the DeletedAt
+// field, i.e. restoring the soft deleted models, matches the deleted rows as well.
+func (p *Postgres) updateSoftDeleteFilter(s *query.Scope, fieldSet mapping.FieldSet) (filters.SQLQuery, bool) {
+	if deletedAt, ok := s.ModelStruct.DeletedAt(); ok && fieldSet.Contains(deletedAt) {
+		return filters.SQLQuery{}, false
+	}
+	return p.softDeleteFilter(s)
+}
+
+func isFieldFilter(field *mapping.StructField, f filter.Filter) bool {
+	switch ft := f.(type) {
+	case filter.Simple:
+		return ft.StructField == field
+	case filter.OrGroup:
+		for _, simple := range ft {
+			if simple.StructField == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (p *Postgres) writeQuotedWord(b *strings.Builder, word string) {
 	nameType, ok := p.keywords[word]
 	if !ok {
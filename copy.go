@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+// DefaultCopyThreshold is the default number of models above which the insert uses the COPY protocol.
+// By default the COPY inserts are disabled.
+const DefaultCopyThreshold = 0
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// isCopyInsert checks if the insert with the common fieldset should use the COPY protocol.
+// The COPY doesn't allow to use column defaults per row, thus it is not used with the InsertDefaults option.
+func (p *Postgres) isCopyInsert(s *query.Scope) bool {
+	return p.CopyThreshold > 0 && len(s.Models) > p.CopyThreshold && !p.isInsertDefaults(s)
+}
+
+// insertWithCopy inserts the scope's models with the common fieldset using the COPY protocol. The COPY doesn't
+// return any values, thus the primary keys are generated or taken from the sequence and the automatically set
+// timestamps are set by the repository. The timestamps are set in the models once the copy succeeds, whereas
+// if the copy fails the assigned primary keys are reset to their zero values.
+func (p *Postgres) insertWithCopy(ctx context.Context, s *query.Scope) error {
+	commonFieldSet, hasCommonFieldSet := s.CommonFieldSet()
+	if !hasCommonFieldSet {
+		return errors.WrapDetf(query.ErrInvalidFieldSet, "no insert fieldset provided")
+	}
+	mStruct := s.ModelStruct
+	fieldSet, autoSelected := p.prepareInsertFieldset(mStruct, commonFieldSet, false)
+	if !fieldSet.Contains(mStruct.Primary()) {
+		fieldSet = append(fieldSet, mStruct.Primary())
+		fieldSet.Sort()
+	}
+
+	assigned, err := p.setCopyPrimaryKeys(ctx, s)
+	if err != nil {
+		return err
+	}
+	rows, timestamps, err := p.copyRows(s, fieldSet, autoSelected)
+	if err != nil {
+		resetPrimaryKeys(mStruct, assigned)
+		return err
+	}
+
+	columns := make([]string, len(fieldSet))
+	for i, field := range fieldSet {
+		columns[i] = field.DatabaseName
+	}
+	if log.Level().IsAllowed(log.LevelDebug3) {
+		log.Debug3f("COPY %s.%s (%s) FROM STDIN - %d rows", mStruct.DatabaseSchemaName, mStruct.DatabaseName, strings.Join(columns, ","), len(rows))
+	}
+	if _, err = p.connection(s).CopyFrom(ctx, pgx.Identifier{mStruct.DatabaseSchemaName, mStruct.DatabaseName}, columns, pgx.CopyFromRows(rows)); err != nil {
+		log.Debugf("Copy insert failed: %v", err)
+		resetPrimaryKeys(mStruct, assigned)
+		return errors.WrapDetf(p.neuronError(err), "inserting failed: %v", err)
+	}
+	for _, timestamp := range timestamps {
+		if err = timestamp.fielder.SetFieldValue(timestamp.field, timestamp.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTimestamp is the automatically set timestamp of the copied model.
+type copyTimestamp struct {
+	fielder mapping.Fielder
+	field   *mapping.StructField
+	value   time.Time
+}
+
+// copyRows gets the 'fieldSet' values of the scope's models. The automatically set timestamps are returned, so that
+// these could be set in the models once the copy succeeds.
+func (p *Postgres) copyRows(s *query.Scope, fieldSet, autoSelected mapping.FieldSet) ([][]interface{}, []copyTimestamp, error) {
+	var now time.Time
+	if p.Clock != nil {
+		now = p.Clock()
+	} else {
+		// The postgres timestamps are stored with the microsecond precision.
+		now = time.Now().Truncate(time.Microsecond)
+	}
+
+	var timestamps []copyTimestamp
+	rows := make([][]interface{}, len(s.Models))
+	for i, model := range s.Models {
+		fielder, ok := model.(mapping.Fielder)
+		if !ok {
+			return nil, nil, errors.Wrapf(mapping.ErrModelNotImplements, "Model: '%s' doesn't implement Fielder interface", s.ModelStruct)
+		}
+		row := make([]interface{}, len(fieldSet))
+		for j, field := range fieldSet {
+			if field.Kind() == mapping.KindPrimary {
+				row[j] = model.GetPrimaryKeyValue()
+				continue
+			}
+			if isTimestampField(field) {
+				autoTimestamp, err := isAutoTimestamp(fielder, field, autoSelected)
+				if err != nil {
+					return nil, nil, err
+				}
+				if autoTimestamp {
+					timestamps = append(timestamps, copyTimestamp{fielder: fielder, field: field, value: now})
+					row[j] = now
+					continue
+				}
+			}
+			var (
+				fieldValue interface{}
+				err        error
+			)
+			if autoSelected != nil && autoSelected.Contains(field) {
+				fieldValue, err = fielder.GetFieldZeroValue(field)
+			} else {
+				fieldValue, err = fielder.GetFieldValue(field)
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			row[j] = fieldValue
+		}
+		rows[i] = row
+	}
+	return rows, timestamps, nil
+}
+
+// setCopyPrimaryKeys sets the primary keys of the models with the zero value primary key. The UUID keys are
+// generated, whereas the integer keys are taken from the primary key column sequence. The models with the assigned
+// primary keys are returned. On error the primary keys are reset to their zero values.
+func (p *Postgres) setCopyPrimaryKeys(ctx context.Context, s *query.Scope) ([]mapping.Model, error) {
+	var models []mapping.Model
+	for _, model := range s.Models {
+		if model.IsPrimaryKeyZero() {
+			models = append(models, model)
+		}
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	primary := s.ModelStruct.Primary()
+	switch t := primary.ReflectField().Type; {
+	case t == uuidType:
+		for _, model := range models {
+			if err := model.SetPrimaryKeyValue(uuid.New()); err != nil {
+				resetPrimaryKeys(s.ModelStruct, models)
+				return nil, err
+			}
+		}
+		return models, nil
+	case isIntegerKind(t.Kind()):
+		values, err := p.nextSequenceValues(ctx, s, len(models))
+		if err != nil {
+			return nil, err
+		}
+		for i, model := range models {
+			if err = model.SetPrimaryKeyValue(values[i]); err != nil {
+				resetPrimaryKeys(s.ModelStruct, models)
+				return nil, err
+			}
+		}
+		return models, nil
+	default:
+		return nil, errors.WrapDetf(query.ErrInvalidModels, "cannot generate primary key values of type: '%s' for the copy insert", t)
+	}
+}
+
+// resetPrimaryKeys sets the primary keys of the 'models' back to their zero values.
+func resetPrimaryKeys(mStruct *mapping.ModelStruct, models []mapping.Model) {
+	zero := reflect.Zero(mStruct.Primary().ReflectField().Type).Interface()
+	for _, model := range models {
+		if err := model.SetPrimaryKeyValue(zero); err != nil {
+			log.Errorf("Resetting model: '%s' primary key failed: %v", mStruct, err)
+		}
+	}
+}
+
+// nextSequenceValues gets 'n' next values of the scope's model primary key sequence.
+func (p *Postgres) nextSequenceValues(ctx context.Context, s *query.Scope, n int) ([]int64, error) {
+	tableName := &strings.Builder{}
+	p.writeTableName(tableName, s.ModelStruct)
+
+	rows, err := p.connection(s).Query(ctx, "SELECT nextval(pg_get_serial_sequence($1, $2)) FROM generate_series(1, $3)",
+		tableName.String(), s.ModelStruct.Primary().DatabaseName, n)
+	if err != nil {
+		return nil, errors.WrapDetf(p.neuronError(err), "getting primary key sequence values failed: %v", err)
+	}
+	defer rows.Close()
+
+	values := make([]int64, 0, n)
+	for rows.Next() {
+		var value *int64
+		if err = rows.Scan(&value); err != nil {
+			return nil, errors.WrapDetf(p.neuronError(err), "getting primary key sequence values failed: %v", err)
+		}
+		if value == nil {
+			return nil, errors.WrapDetf(query.ErrInvalidModels, "model: '%s' primary key has no sequence", s.ModelStruct)
+		}
+		values = append(values, *value)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapDetf(p.neuronError(err), "getting primary key sequence values failed: %v", err)
+	}
+	return values, nil
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
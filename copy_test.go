@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+)
+
+func TestCopyRows(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	now := time.Date(2020, 7, 17, 12, 0, 0, 0, time.UTC)
+	p.Clock = func() time.Time { return now }
+	defer func() {
+		p.Clock = nil
+	}()
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := &tests.Model{ID: 1, AttrString: "first", CreatedAt: created}
+	second := &tests.Model{ID: 2, AttrString: "second"}
+	s := query.NewScope(mStruct, first, second)
+
+	fieldSet, autoSelected := p.prepareInsertFieldset(mStruct, mapping.FieldSet{mStruct.Primary(), mStruct.MustFieldByName("AttrString"), mStruct.MustFieldByName("CreatedAt")}, false)
+	rows, timestamps, err := p.copyRows(s, fieldSet, autoSelected)
+	require.NoError(t, err)
+
+	// id, attr_string, int, created_at, updated_at
+	require.Len(t, rows, 2)
+	assert.Equal(t, []interface{}{1, "first", 0, created, now}, rows[0])
+	assert.Equal(t, []interface{}{2, "second", 0, now, now}, rows[1])
+
+	// The zero value CreatedAt and the UpdatedAt timestamps are not set in the models until the copy succeeds.
+	assert.True(t, second.CreatedAt.IsZero())
+	assert.Nil(t, second.UpdatedAt)
+	if assert.Len(t, timestamps, 3) {
+		for _, timestamp := range timestamps {
+			assert.Equal(t, now, timestamp.value)
+		}
+		assert.Equal(t, first, timestamps[0].fielder)
+		assert.Equal(t, second, timestamps[1].fielder)
+		assert.Equal(t, second, timestamps[2].fielder)
+	}
+}
+
+func TestSetCopyPrimaryKeys(t *testing.T) {
+	c := testingController(t, false, &tests.ArrayModel{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.ArrayModel{})
+	require.NoError(t, err)
+
+	first, second := &tests.ArrayModel{}, &tests.ArrayModel{}
+	s := query.NewScope(mStruct, first, second)
+	assigned, err := p.setCopyPrimaryKeys(context.Background(), s)
+	require.NoError(t, err)
+	assert.Len(t, assigned, 2)
+
+	assert.False(t, first.IsPrimaryKeyZero())
+	assert.False(t, second.IsPrimaryKeyZero())
+	assert.NotEqual(t, first.ID, second.ID)
+
+	resetPrimaryKeys(mStruct, assigned)
+	assert.True(t, first.IsPrimaryKeyZero())
+	assert.True(t, second.IsPrimaryKeyZero())
+}
+
+func TestIsCopyInsert(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	p.CopyThreshold = 1
+	defer func() {
+		p.CopyThreshold = DefaultCopyThreshold
+	}()
+
+	assert.False(t, p.isCopyInsert(query.NewScope(mStruct, &tests.Model{})))
+	s := query.NewScope(mStruct, &tests.Model{}, &tests.Model{})
+	assert.True(t, p.isCopyInsert(s))
+
+	InsertDefaults(s)
+	assert.False(t, p.isCopyInsert(s))
+}
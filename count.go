@@ -48,6 +48,10 @@ func (p *Postgres) parseCountQuery(s *query.Scope) (*simpleQuery, error) {
 		return nil, err
 	}
 
+	if softDeleted, ok := p.softDeleteFilter(s); ok {
+		parsedFilters = append(parsedFilters, softDeleted)
+	}
+
 	q := &simpleQuery{}
 
 	if len(parsedFilters) > 0 {
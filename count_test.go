@@ -26,10 +26,39 @@ func TestParseCount(t *testing.T) {
 	q, err := p.parseCountQuery(s)
 	require.NoError(t, err)
 
-	assert.Equal(t, "SELECT COUNT(DISTINCT id) FROM public.models WHERE id IN ($1,$2)", q.query)
+	assert.Equal(t, "SELECT COUNT(DISTINCT id) FROM public.models WHERE id IN ($1,$2) AND deleted_at IS NULL", q.query)
 	assert.ElementsMatch(t, []interface{}{12, 23}, q.values)
 }
 
+// TestParseCountSoftDeleted tests the soft delete filter of the count query.
+func TestParseCountSoftDeleted(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+	deletedAt, ok := mStruct.DeletedAt()
+	require.True(t, ok)
+
+	t.Run("IncludeDeleted", func(t *testing.T) {
+		s := query.NewScope(mStruct)
+		IncludeDeleted(s)
+
+		q, err := p.parseCountQuery(s)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(DISTINCT id) FROM public.models", q.query)
+	})
+
+	t.Run("DeletedAtFilter", func(t *testing.T) {
+		s := query.NewScope(mStruct)
+		s.Filters = filter.Filters{filter.New(deletedAt, filter.OpNotNull)}
+
+		q, err := p.parseCountQuery(s)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT COUNT(DISTINCT id) FROM public.models WHERE deleted_at IS NOT NULL", q.query)
+	})
+}
+
 //
 // 	p := &Postgres{}
 //
@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/filters"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+)
+
+// cursor is the encoded keyset pagination position.
+type cursor struct {
+	// Backward defines if the cursor points to the page preceding given position.
+	Backward bool `json:"b,omitempty"`
+	// Values are the sort fields values of the position row.
+	Values []json.RawMessage `json:"v"`
+}
+
+// pageCursors are the next and previous page cursors of the find query.
+type pageCursors struct {
+	next, prev string
+}
+
+// cursorPagination is the keyset pagination of the select query.
+type cursorPagination struct {
+	// backward defines if the query selects the page preceding the cursor position.
+	backward bool
+	// fields are the cursor fields - the sort fields followed by the primary key.
+	fields []*mapping.StructField
+	// values are the decoded cursor values. Nil for the first page.
+	values []interface{}
+	// rawValues are the encoded cursor values.
+	rawValues []json.RawMessage
+}
+
+// prepareCursorPagination decodes the scope's cursor and sets up the query sorting order. The primary key is added
+// to the sorting order as the tiebreaker. For the backward cursor the sorting order is reversed, so that
+// the rows closest to the cursor position are selected first.
+func prepareCursorPagination(s *query.Scope, q *selectQuery) error {
+	v, ok := s.StoreGet(internal.CursorKey)
+	if !ok {
+		return nil
+	}
+	encoded, ok := v.(string)
+	if !ok {
+		return errors.WrapDetf(query.ErrInvalidParameter, "invalid cursor type: '%T'", v)
+	}
+	if s.Pagination == nil || s.Pagination.Limit == 0 {
+		return errors.WrapDet(query.ErrInvalidParameter, "cursor pagination requires the limit")
+	}
+	if s.Pagination.Offset != 0 {
+		return errors.WrapDet(query.ErrInvalidParameter, "cursor pagination cannot be used with the offset")
+	}
+
+	q.cursor = &cursorPagination{}
+	sorts := make([]query.Sort, 0, len(s.SortingOrder)+1)
+	hasPrimary := false
+	for _, sort := range s.SortingOrder {
+		if _, ok := sort.(query.SortField); !ok {
+			return errors.WrapDetf(query.ErrInvalidSort, "cursor pagination doesn't support sorting by: '%s'", sort.Field())
+		}
+		if sort.Field() == s.ModelStruct.Primary() {
+			hasPrimary = true
+		}
+		sorts = append(sorts, sort)
+	}
+	if !hasPrimary {
+		// The primary key is the tiebreaker with the same order as the last sort field, so that if all the fields
+		// have the same order the row comparison could be used.
+		order := query.AscendingOrder
+		if len(sorts) > 0 {
+			order = sorts[len(sorts)-1].Order()
+		}
+		sorts = append(sorts, query.SortField{StructField: s.ModelStruct.Primary(), SortOrder: order})
+	}
+
+	if encoded != "" {
+		c, err := decodeCursor(encoded)
+		if err != nil {
+			return err
+		}
+		if len(c.Values) != len(sorts) {
+			return errors.WrapDet(query.ErrInvalidParameter, "cursor doesn't match query sorting order")
+		}
+		q.cursor.backward = c.Backward
+		q.cursor.rawValues = c.Values
+		for i, sort := range sorts {
+			value := reflect.New(sort.Field().ReflectField().Type)
+			if err = json.Unmarshal(c.Values[i], value.Interface()); err != nil {
+				return errors.WrapDetf(query.ErrInvalidParameter, "invalid cursor value for the field: '%s'", sort.Field())
+			}
+			q.cursor.values = append(q.cursor.values, value.Elem().Interface())
+		}
+	}
+
+	for i, sort := range sorts {
+		q.cursor.fields = append(q.cursor.fields, sort.Field())
+		if q.cursor.backward {
+			order := query.DescendingOrder
+			if sort.Order() == query.DescendingOrder {
+				order = query.AscendingOrder
+			}
+			sorts[i] = query.SortField{StructField: sort.Field(), SortOrder: order}
+		}
+		// The cursor fields needs to be scanned in order to encode the page cursors.
+		if !containsField(q.fieldsOrder, sort.Field()) {
+			q.fieldsOrder = append(q.fieldsOrder, sort.Field())
+		}
+	}
+	q.sorts = sorts
+	return nil
+}
+
+// parseSelectCursor creates the keyset predicate for the cursor values. If all the sort fields have the same order
+// the row comparison is used:
+//
+//	(created_at, id) > ($1, $2)
+//
+// otherwise the predicate is expanded for each sort field:
+//
+//	(created_at > $1 OR (created_at = $1 AND id < $2))
+func (p *Postgres) parseSelectCursor(s *query.Scope, q *selectQuery) filters.SQLQuery {
+	placeholders := make([]string, len(q.sorts))
+	for i := range q.sorts {
+		placeholders[i] = internal.StringIncrementor(s)
+	}
+	sq := filters.SQLQuery{Values: q.cursor.values}
+
+	sameOrder := true
+	for _, sort := range q.sorts[1:] {
+		if sort.Order() != q.sorts[0].Order() {
+			sameOrder = false
+			break
+		}
+	}
+
+	sb := &strings.Builder{}
+	if len(q.sorts) == 1 || sameOrder {
+		if len(q.sorts) > 1 {
+			sb.WriteRune('(')
+		}
+		for i, sort := range q.sorts {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			p.writeQuotedWord(sb, sort.Field().DatabaseName)
+		}
+		if len(q.sorts) > 1 {
+			sb.WriteRune(')')
+		}
+		sb.WriteString(cursorOperator(q.sorts[0]))
+		if len(q.sorts) > 1 {
+			sb.WriteRune('(')
+		}
+		sb.WriteString(strings.Join(placeholders, ", "))
+		if len(q.sorts) > 1 {
+			sb.WriteRune(')')
+		}
+		sq.Query = sb.String()
+		return sq
+	}
+
+	sb.WriteRune('(')
+	for i, sort := range q.sorts {
+		if i != 0 {
+			sb.WriteString(" OR (")
+			for j := 0; j < i; j++ {
+				p.writeQuotedWord(sb, q.sorts[j].Field().DatabaseName)
+				sb.WriteString(" = ")
+				sb.WriteString(placeholders[j])
+				sb.WriteString(" AND ")
+			}
+		}
+		p.writeQuotedWord(sb, sort.Field().DatabaseName)
+		sb.WriteString(cursorOperator(sort))
+		sb.WriteString(placeholders[i])
+		if i != 0 {
+			sb.WriteRune(')')
+		}
+	}
+	sb.WriteRune(')')
+	sq.Query = sb.String()
+	return sq
+}
+
+func cursorOperator(sort query.Sort) string {
+	if sort.Order() == query.DescendingOrder {
+		return " < "
+	}
+	return " > "
+}
+
+// setPageCursors trims the additional row selected by the cursor query, restores the order of the backward page
+// and stores the next and previous page cursors in the scope.
+func setPageCursors(s *query.Scope, q *selectQuery) (err error) {
+	hasMore := int64(len(s.Models)) > s.Pagination.Limit
+	if hasMore {
+		s.Models = s.Models[:s.Pagination.Limit]
+	}
+	if q.cursor.backward {
+		for i, j := 0, len(s.Models)-1; i < j; i, j = i+1, j-1 {
+			s.Models[i], s.Models[j] = s.Models[j], s.Models[i]
+		}
+	}
+
+	var cursors pageCursors
+	if len(s.Models) == 0 {
+		// An empty page could still point back to the cursor position.
+		if q.cursor.rawValues != nil {
+			c := cursor{Backward: !q.cursor.backward, Values: q.cursor.rawValues}
+			if q.cursor.backward {
+				cursors.next, err = encodeCursor(c)
+			} else {
+				cursors.prev, err = encodeCursor(c)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		s.StoreSet(internal.PageCursorsKey, cursors)
+		return nil
+	}
+
+	first, last := s.Models[0], s.Models[len(s.Models)-1]
+	if q.cursor.backward {
+		if hasMore {
+			if cursors.prev, err = modelCursor(first, q.cursor.fields, true); err != nil {
+				return err
+			}
+		}
+		if cursors.next, err = modelCursor(last, q.cursor.fields, false); err != nil {
+			return err
+		}
+	} else {
+		if hasMore {
+			if cursors.next, err = modelCursor(last, q.cursor.fields, false); err != nil {
+				return err
+			}
+		}
+		if q.cursor.values != nil {
+			if cursors.prev, err = modelCursor(first, q.cursor.fields, true); err != nil {
+				return err
+			}
+		}
+	}
+	s.StoreSet(internal.PageCursorsKey, cursors)
+	return nil
+}
+
+func modelCursor(model mapping.Model, fields []*mapping.StructField, backward bool) (string, error) {
+	fielder, ok := model.(mapping.Fielder)
+	if !ok {
+		return "", errors.Wrapf(mapping.ErrModelNotImplements, "Model: '%T' doesn't implement Fielder interface", model)
+	}
+	c := cursor{Backward: backward}
+	for _, field := range fields {
+		value, err := fielder.GetFieldValue(field)
+		if err != nil {
+			return "", err
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", errors.Wrapf(query.ErrInternal, "marshaling cursor value for the field: '%s' failed: %v", field, err)
+		}
+		c.Values = append(c.Values, raw)
+	}
+	return encodeCursor(c)
+}
+
+func encodeCursor(c cursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.Wrapf(query.ErrInternal, "marshaling cursor failed: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.WrapDet(query.ErrInvalidParameter, "invalid cursor encoding")
+	}
+	c := &cursor{}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, errors.WrapDet(query.ErrInvalidParameter, "invalid cursor")
+	}
+	return c, nil
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+)
+
+func TestParseSelectCursor(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	repo := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	attrField, ok := mStruct.Attribute("attr_string")
+	require.True(t, ok)
+	intField, ok := mStruct.Attribute("int")
+	require.True(t, ok)
+
+	newScope := func(cursor string, sorts ...query.Sort) *query.Scope {
+		s := query.NewScope(mStruct)
+		s.FieldSets = []mapping.FieldSet{{mStruct.Primary(), attrField}}
+		s.SortingOrder = sorts
+		s.Pagination = &query.Pagination{Limit: 2}
+		WithCursor(s, cursor)
+		return s
+	}
+
+	t.Run("FirstPage", func(t *testing.T) {
+		s := newScope("", query.SortField{StructField: attrField, SortOrder: query.AscendingOrder})
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT id, attr_string FROM public.models WHERE deleted_at IS NULL ORDER BY attr_string ASC, id ASC LIMIT $1", sq.query)
+		assert.Equal(t, []interface{}{int64(3)}, sq.values)
+	})
+
+	t.Run("NextPage", func(t *testing.T) {
+		s := newScope("", query.SortField{StructField: attrField, SortOrder: query.AscendingOrder})
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		s.Models = []mapping.Model{&tests.Model{ID: 1, AttrString: "a"}, &tests.Model{ID: 2, AttrString: "b"}, &tests.Model{ID: 3, AttrString: "c"}}
+		require.NoError(t, setPageCursors(s, sq))
+		assert.Len(t, s.Models, 2)
+
+		next, prev := PageCursors(s)
+		assert.Empty(t, prev)
+		require.NotEmpty(t, next)
+
+		s = newScope(next, query.SortField{StructField: attrField, SortOrder: query.AscendingOrder})
+		sq, err = repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT id, attr_string FROM public.models WHERE deleted_at IS NULL AND (attr_string, id) > ($1, $2) ORDER BY attr_string ASC, id ASC LIMIT $3", sq.query)
+		assert.Equal(t, []interface{}{"b", 2, int64(3)}, sq.values)
+	})
+
+	t.Run("MixedOrder", func(t *testing.T) {
+		cursor, err := modelCursor(&tests.Model{ID: 4, AttrString: "d", Int: 10}, []*mapping.StructField{intField, attrField, mStruct.Primary()}, false)
+		require.NoError(t, err)
+
+		s := newScope(cursor,
+			query.SortField{StructField: intField, SortOrder: query.DescendingOrder},
+			query.SortField{StructField: attrField, SortOrder: query.AscendingOrder},
+		)
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT id, attr_string, int FROM public.models WHERE deleted_at IS NULL AND (int < $1 OR (int = $1 AND attr_string > $2) OR (int = $1 AND attr_string = $2 AND id > $3)) ORDER BY int DESC, attr_string ASC, id ASC LIMIT $4", sq.query)
+		assert.Equal(t, []interface{}{10, "d", 4, int64(3)}, sq.values)
+	})
+
+	t.Run("PreviousPage", func(t *testing.T) {
+		cursor, err := modelCursor(&tests.Model{ID: 4, AttrString: "d"}, []*mapping.StructField{attrField, mStruct.Primary()}, true)
+		require.NoError(t, err)
+
+		s := newScope(cursor, query.SortField{StructField: attrField, SortOrder: query.AscendingOrder})
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT id, attr_string FROM public.models WHERE deleted_at IS NULL AND (attr_string, id) < ($1, $2) ORDER BY attr_string DESC, id DESC LIMIT $3", sq.query)
+
+		s.Models = []mapping.Model{&tests.Model{ID: 3, AttrString: "c"}, &tests.Model{ID: 2, AttrString: "b"}, &tests.Model{ID: 1, AttrString: "a"}}
+		require.NoError(t, setPageCursors(s, sq))
+		if assert.Len(t, s.Models, 2) {
+			assert.Equal(t, 2, s.Models[0].GetPrimaryKeyValue())
+			assert.Equal(t, 3, s.Models[1].GetPrimaryKeyValue())
+		}
+		next, prev := PageCursors(s)
+		assert.NotEmpty(t, next)
+		assert.NotEmpty(t, prev)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		s := newScope("invalid")
+		_, err := repo.parseSelectQuery(s)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, query.ErrInvalidParameter))
+
+		s = newScope("")
+		s.Pagination.Offset = 2
+		_, err = repo.parseSelectQuery(s)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, query.ErrInvalidParameter))
+	})
+}
@@ -5,12 +5,16 @@ import (
 	"strings"
 
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/filters"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
 	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 )
 
-// Delete deletes all the values that matches scope's filters.
+// Delete deletes all the values that matches scope's filters. If the scope contains models, only the models
+// with matching primary keys are deleted. With the ReturnDeleted option the deleted rows are set as the scope's models.
 // Implements repository.Repository interface.
 func (p *Postgres) Delete(ctx context.Context, s *query.Scope) (int64, error) {
 	q, err := p.parseDeleteQuery(s)
@@ -22,6 +26,10 @@ func (p *Postgres) Delete(ctx context.Context, s *query.Scope) (int64, error) {
 		log.Debug2f("[DELETE] %s", q.query)
 	}
 
+	if len(q.returning) > 0 {
+		return p.deleteReturning(ctx, s, q)
+	}
+
 	// Execute prepared query.
 	res, err := p.connection(s).Exec(ctx, q.query, q.values...)
 	if err != nil {
@@ -31,26 +39,122 @@ func (p *Postgres) Delete(ctx context.Context, s *query.Scope) (int64, error) {
 	return res.RowsAffected(), nil
 }
 
+// deleteReturning executes the delete query that returns the deleted rows. The rows are set as the scope's models
+// if the ReturnDeleted option is set. If the query deletes the models with the version field, the models that
+// were not deleted are stale.
+func (p *Postgres) deleteReturning(ctx context.Context, s *query.Scope, q *deleteQuery) (int64, error) {
+	rows, err := p.connection(s).Query(ctx, q.query, q.values...)
+	if err != nil {
+		return 0, errors.Wrap(p.neuronError(err), "delete query failed")
+	}
+	defer rows.Close()
+
+	models := s.Models
+	s.Models = nil
+	sq := &selectQuery{fieldsOrder: q.returning}
+	for rows.Next() {
+		if err = p.scanRow(s, sq, rows); err != nil {
+			s.Models = models
+			return 0, errors.Wrap(p.neuronError(err), "scanning deleted row failed")
+		}
+	}
+	if err = rows.Err(); err != nil {
+		s.Models = models
+		return 0, errors.Wrap(p.neuronError(err), "delete query failed")
+	}
+	deleted := s.Models
+	if !q.returnDeleted {
+		s.Models = models
+	}
+	if q.version == nil {
+		return int64(len(deleted)), nil
+	}
+
+	deletedKeys := map[interface{}]struct{}{}
+	for _, model := range deleted {
+		deletedKeys[model.GetPrimaryKeyHashableValue()] = struct{}{}
+	}
+	var stale []mapping.Model
+	for _, model := range models {
+		if _, ok := deletedKeys[model.GetPrimaryKeyHashableValue()]; !ok {
+			stale = append(stale, model)
+		}
+	}
+	if len(stale) > 0 {
+		return int64(len(deleted)), &StaleModelsError{Models: stale}
+	}
+	return int64(len(deleted)), nil
+}
+
 type simpleQuery struct {
 	query  string
 	values []interface{}
 }
 
-func (p *Postgres) parseDeleteQuery(s *query.Scope) (*simpleQuery, error) {
+// deleteQuery is the delete query with the optional version field of the deleted models.
+type deleteQuery struct {
+	simpleQuery
+	// version is the optimistic locking version field checked for the scope's models.
+	version *mapping.StructField
+	// returning are the fields of the deleted rows returned by the query.
+	returning []*mapping.StructField
+	// returnDeleted defines if the returned rows are set as the scope's models.
+	returnDeleted bool
+}
+
+func (p *Postgres) parseDeleteQuery(s *query.Scope) (*deleteQuery, error) {
 	var sb strings.Builder
 
 	mStruct := s.ModelStruct
-	sb.WriteString("DELETE FROM ")
+	// The models with the DeletedAt field are soft deleted unless the HardDelete option is set.
+	deletedAt, softDelete := mStruct.DeletedAt()
+	if softDelete && isHardDelete(s) {
+		softDelete = false
+	}
+	if softDelete {
+		sb.WriteString("UPDATE ")
+	} else {
+		sb.WriteString("DELETE FROM ")
+	}
 	p.writeQuotedWord(&sb, mStruct.DatabaseSchemaName)
 	sb.WriteRune('.')
 	p.writeQuotedWord(&sb, mStruct.DatabaseName)
+	if softDelete {
+		sb.WriteString(" SET ")
+		p.writeQuotedWord(&sb, deletedAt.DatabaseName)
+		sb.WriteString(" = now()")
+	}
+	version, hasVersion := migrate.VersionField(mStruct)
+	if softDelete && hasVersion {
+		sb.WriteString(", ")
+		p.writeVersionIncrement(&sb, version)
+	}
 
-	parsedFilters, err := filters.ParseFilters(s, p.writeQuotedWord)
+	parsedFilters, err := p.parseModifyFilters(s)
 	if err != nil {
 		return nil, err
 	}
+	if softDelete {
+		// Don't change the DeletedAt timestamp of the already deleted models.
+		if softDeleted, ok := p.softDeleteFilter(s); ok {
+			parsedFilters = append(parsedFilters, softDeleted)
+		}
+	}
 
-	q := &simpleQuery{}
+	q := &deleteQuery{}
+	if len(s.Models) > 0 {
+		primaryKeys, err := modelsPrimaryKeys(s)
+		if err != nil {
+			return nil, err
+		}
+		if hasVersion {
+			// The versioned models are deleted only if their versions were not changed in the meantime.
+			q.version = version
+			parsedFilters = append(parsedFilters, p.versionFilter(s, version))
+		} else {
+			parsedFilters = append(parsedFilters, p.primaryKeysFilter(s, primaryKeys))
+		}
+	}
 	// check if there is any filter
 	if len(parsedFilters) > 0 {
 		sb.WriteString(" WHERE ")
@@ -62,6 +166,136 @@ func (p *Postgres) parseDeleteQuery(s *query.Scope) (*simpleQuery, error) {
 			q.values = append(q.values, sq.Values...)
 		}
 	}
+	if isReturnDeleted(s) {
+		q.returnDeleted = true
+		q.returning = deleteReturningFields(s)
+	}
+	if q.version != nil && !containsField(q.returning, mStruct.Primary()) {
+		// The primary keys of the deleted versioned models are required to find the stale ones.
+		q.returning = append(q.returning, mStruct.Primary())
+	}
+	p.writeReturning(&sb, q.returning)
 	q.query = sb.String()
 	return q, nil
 }
+
+// deleteReturningFields gets the fields of the deleted rows returned by the delete query. These are the fields from
+// the scope's common field set or all the model's fields if the scope has no field set.
+func deleteReturningFields(s *query.Scope) (fields []*mapping.StructField) {
+	fieldSet, ok := s.CommonFieldSet()
+	if !ok || len(fieldSet) == 0 {
+		fieldSet = s.ModelStruct.Fields()
+	}
+	for _, field := range fieldSet {
+		if !field.DatabaseSkip() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// modelsPrimaryKeys gets the primary key values of the scope's models. Returns an error if any of the models
+// has zero primary key value.
+func modelsPrimaryKeys(s *query.Scope) ([]interface{}, error) {
+	primaryKeys := make([]interface{}, len(s.Models))
+	for i, model := range s.Models {
+		if model.IsPrimaryKeyZero() {
+			return nil, errors.WrapDetf(query.ErrInvalidModels, "model at index: %d has zero primary key value", i)
+		}
+		primaryKeys[i] = model.GetPrimaryKeyValue()
+	}
+	return primaryKeys, nil
+}
+
+// primaryKeysFilter creates the filter that matches the 'primaryKeys' of the scope's models
+// i.e.: 'id = ANY($1)'. The primary keys that couldn't be written as an array are written as the IN clause.
+func (p *Postgres) primaryKeysFilter(s *query.Scope, primaryKeys []interface{}) filters.SQLQuery {
+	sb := &strings.Builder{}
+	sq := filters.SQLQuery{}
+	p.writeQuotedWord(sb, s.ModelStruct.Primary().DatabaseName)
+	if array, ok := internal.ArrayValue(primaryKeys); ok {
+		sb.WriteString(" = ANY(")
+		sb.WriteString(internal.StringIncrementor(s))
+		sb.WriteRune(')')
+		sq.Values = []interface{}{array}
+	} else {
+		sq.Values = writeInValues(s, sb, primaryKeys)
+	}
+	sq.Query = sb.String()
+	return sq
+}
+
+// versionFilter creates the filter that matches the scope's models primary keys with their versions
+// i.e.: '(id, version) IN (($1,$2),($3,$4))'. Large number of models is matched with the typed arrays of their
+// primary keys and versions, so that the query doesn't exceed the parameters limit
+// i.e.: '(id, version) IN (SELECT * FROM unnest($1::integer[], $2::integer[]))'.
+func (p *Postgres) versionFilter(s *query.Scope, version *mapping.StructField) filters.SQLQuery {
+	primaryKeys := make([]interface{}, len(s.Models))
+	versions := make([]interface{}, len(s.Models))
+	for i, model := range s.Models {
+		primaryKeys[i] = model.GetPrimaryKeyValue()
+		if fielder, ok := model.(mapping.Fielder); ok {
+			versions[i], _ = fielder.GetFieldValue(version)
+		}
+	}
+
+	sb := &strings.Builder{}
+	sq := filters.SQLQuery{}
+	sb.WriteRune('(')
+	p.writeQuotedWord(sb, s.ModelStruct.Primary().DatabaseName)
+	sb.WriteString(", ")
+	p.writeQuotedWord(sb, version.DatabaseName)
+	sb.WriteString(") IN (")
+	if len(s.Models) > internal.InArrayThreshold {
+		if arrays, types, ok := versionArrays(s.ModelStruct.Primary(), version, primaryKeys, versions); ok {
+			sb.WriteString("SELECT * FROM unnest(")
+			for i, columnType := range types {
+				if i != 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(internal.StringIncrementor(s))
+				sb.WriteString("::")
+				sb.WriteString(columnType)
+				sb.WriteString("[]")
+			}
+			sb.WriteString("))")
+			sq.Values = arrays
+			sq.Query = sb.String()
+			return sq
+		}
+	}
+	for i := range s.Models {
+		if i != 0 {
+			sb.WriteRune(',')
+		}
+		sb.WriteRune('(')
+		sb.WriteString(internal.StringIncrementor(s))
+		sb.WriteRune(',')
+		sb.WriteString(internal.StringIncrementor(s))
+		sb.WriteRune(')')
+		sq.Values = append(sq.Values, primaryKeys[i], versions[i])
+	}
+	sb.WriteRune(')')
+	sq.Query = sb.String()
+	return sq
+}
+
+// versionArrays gets the typed arrays of the 'primaryKeys' and 'versions' along with their column types.
+// Returns false if any of the values couldn't be written as the typed array.
+func versionArrays(primary, version *mapping.StructField, primaryKeys, versions []interface{}) ([]interface{}, []string, bool) {
+	fields, values := []*mapping.StructField{primary, version}, [][]interface{}{primaryKeys, versions}
+	arrays, types := make([]interface{}, len(fields)), make([]string, len(fields))
+	for i, field := range fields {
+		columnType, err := migrate.ColumnType(field)
+		// The unnest function flattens the multidimensional arrays.
+		if err != nil || strings.HasSuffix(columnType, "]") {
+			return nil, nil, false
+		}
+		array, ok := internal.TypedArrayValue(field.ReflectField().Type, values[i])
+		if !ok {
+			return nil, nil, false
+		}
+		arrays[i], types[i] = array, columnType
+	}
+	return arrays, types, true
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
 	"github.com/neuronlabs/neuron/database"
 	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 )
 
 // TestIntegrationDelete integration tests for the deleteQuery processes.
@@ -63,6 +65,26 @@ func TestIntegrationDelete(t *testing.T) {
 
 		assert.Equal(t, int64(2), affected)
 	})
+
+	t.Run("Returning", func(t *testing.T) {
+		model := newModel()
+		model2 := newModel()
+		err = db.Query(mStruct, model, model2).Insert()
+		require.NoError(t, err)
+
+		s := query.NewScope(mStruct)
+		s.Filters = filter.Filters{filter.New(mStruct.Primary(), filter.OpIn, model.ID, model2.ID)}
+		ReturnDeleted(s)
+		affected, err := p.Delete(ctx, s)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), affected)
+		if assert.Len(t, s.Models, 2) {
+			for _, deleted := range s.Models {
+				assert.Equal(t, "Something", deleted.(*tests.SimpleModel).Attr)
+			}
+		}
+	})
 }
 
 func TestSoftDelete(t *testing.T) {
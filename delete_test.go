@@ -6,7 +6,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/query/filter"
 )
@@ -26,6 +29,192 @@ func TestParseDeleteQuery(t *testing.T) {
 	q, err := p.parseDeleteQuery(s)
 	require.NoError(t, err)
 
+	// Models with the DeletedAt field are soft deleted.
+	assert.Equal(t, "UPDATE public.models SET deleted_at = now() WHERE id IN ($1,$2) AND deleted_at IS NULL", q.query)
+	assert.ElementsMatch(t, q.values, []interface{}{3, 10})
+
+	s = query.NewScope(mStruct)
+	s.Filters = filter.Filters{
+		filter.New(mStruct.Primary(), filter.OpIn, 3, 10),
+	}
+	HardDelete(s)
+	q, err = p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
 	assert.Equal(t, "DELETE FROM public.models WHERE id IN ($1,$2)", q.query)
 	assert.ElementsMatch(t, q.values, []interface{}{3, 10})
 }
+
+// TestParseDeleteOmittedFilters tests the delete query with all filters omitted.
+func TestParseDeleteOmittedFilters(t *testing.T) {
+	c := testingController(t, false, &tests.OmitModel{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.OmitModel{})
+	require.NoError(t, err)
+
+	s := query.NewScope(mStruct)
+	s.Filters = filter.Filters{
+		filter.New(mStruct.MustFieldByName("OmitField"), filter.OpEqual, "omitted"),
+	}
+	_, err = p.parseDeleteQuery(s)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, filter.ErrFilterField))
+
+	AllowFullTable(s)
+	q, err := p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM public.omit_models", q.query)
+}
+
+// TestParseDeletePartiallyOmittedFilters tests the delete query with some of the filters omitted.
+func TestParseDeletePartiallyOmittedFilters(t *testing.T) {
+	c := testingController(t, false, &tests.OmitModel{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.OmitModel{})
+	require.NoError(t, err)
+
+	s := query.NewScope(mStruct)
+	s.Filters = filter.Filters{
+		filter.New(mStruct.Primary(), filter.OpGreaterThan, 3),
+		filter.New(mStruct.MustFieldByName("OmitField"), filter.OpEqual, "omitted"),
+	}
+	// Omitting the filter would widen the delete query.
+	_, err = p.parseDeleteQuery(s)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, filter.ErrFilterField))
+
+	s = query.NewScope(mStruct)
+	s.Filters = filter.Filters{
+		filter.New(mStruct.Primary(), filter.OpGreaterThan, 3),
+		filter.New(mStruct.MustFieldByName("OmitField"), filter.OpEqual, "omitted"),
+	}
+	AllowFullTable(s)
+	q, err := p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM public.omit_models WHERE id > $1", q.query)
+
+	// Without the strict filters the omitted filter is skipped.
+	p.StrictFilters = false
+	s = query.NewScope(mStruct)
+	s.Filters = filter.Filters{
+		filter.New(mStruct.Primary(), filter.OpGreaterThan, 3),
+		filter.New(mStruct.MustFieldByName("OmitField"), filter.OpEqual, "omitted"),
+	}
+	q, err = p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM public.omit_models WHERE id > $1", q.query)
+}
+
+// TestParseDeleteVersionedModels tests the delete query of the models with the version field.
+func TestParseDeleteVersionedModels(t *testing.T) {
+	c := testingController(t, false, &tests.VersionedModel{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.VersionedModel{})
+	require.NoError(t, err)
+
+	s := query.NewScope(mStruct, &tests.VersionedModel{ID: 3, Version: 1}, &tests.VersionedModel{ID: 10, Version: 4})
+	s.Filters = filter.Filters{
+		filter.New(mStruct.Primary(), filter.OpIn, 3, 10),
+	}
+	q, err := p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM public.versioned_models WHERE id IN ($1,$2) AND (id, version) IN (($3,$4),($5,$6)) RETURNING id", q.query)
+	assert.Equal(t, []interface{}{3, 10, 3, 1, 10, 4}, q.values)
+	assert.Equal(t, mStruct.MustFieldByName("Version"), q.version)
+}
+
+// TestParseDeleteManyVersionedModels tests the delete query of the versioned models exceeding the IN values threshold.
+func TestParseDeleteManyVersionedModels(t *testing.T) {
+	c := testingController(t, false, &tests.VersionedModel{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.VersionedModel{})
+	require.NoError(t, err)
+
+	models := make([]mapping.Model, internal.MaxParameters)
+	for i := range models {
+		models[i] = &tests.VersionedModel{ID: i + 1, Version: i % 3}
+	}
+	s := query.NewScope(mStruct, models...)
+	q, err := p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	// The primary keys and versions are sent as the typed arrays, so that the parameters limit is not exceeded.
+	assert.Equal(t, "DELETE FROM public.versioned_models WHERE (id, version) IN (SELECT * FROM unnest($1::integer[], $2::integer[])) RETURNING id", q.query)
+	if assert.Len(t, q.values, 2) {
+		assert.Len(t, q.values[0], internal.MaxParameters)
+		assert.Equal(t, []int{0, 1, 2}, q.values[1].([]int)[:3])
+	}
+}
+
+// TestParseDeleteModels tests the delete query of the scope's models.
+func TestParseDeleteModels(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	s := query.NewScope(mStruct, &tests.Model{ID: 3}, &tests.Model{ID: 10})
+	HardDelete(s)
+	q, err := p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM public.models WHERE id = ANY($1)", q.query)
+	assert.Equal(t, []interface{}{[]int64{3, 10}}, q.values)
+
+	s = query.NewScope(mStruct, &tests.Model{ID: 3}, &tests.Model{ID: 10})
+	s.Filters = filter.Filters{
+		filter.New(mStruct.MustFieldByName("Int"), filter.OpGreaterThan, 2),
+	}
+	q, err = p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "UPDATE public.models SET deleted_at = now() WHERE int > $1 AND deleted_at IS NULL AND id = ANY($2)", q.query)
+	assert.Equal(t, []interface{}{2, []int64{3, 10}}, q.values)
+
+	s = query.NewScope(mStruct, &tests.Model{ID: 3}, &tests.Model{})
+	_, err = p.parseDeleteQuery(s)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, query.ErrInvalidModels))
+}
+
+// TestParseDeleteReturning tests the delete query that returns the deleted rows.
+func TestParseDeleteReturning(t *testing.T) {
+	c := testingController(t, false, &tests.Model{}, &tests.VersionedModel{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	s := query.NewScope(mStruct)
+	s.Filters = filter.Filters{
+		filter.New(mStruct.MustFieldByName("Int"), filter.OpGreaterThan, 2),
+	}
+	s.FieldSets = []mapping.FieldSet{{mStruct.Primary(), mStruct.MustFieldByName("AttrString")}}
+	HardDelete(s)
+	ReturnDeleted(s)
+	q, err := p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM public.models WHERE int > $1 RETURNING id, attr_string", q.query)
+	assert.True(t, q.returnDeleted)
+
+	versioned, err := c.ModelStruct(&tests.VersionedModel{})
+	require.NoError(t, err)
+
+	s = query.NewScope(versioned, &tests.VersionedModel{ID: 3, Version: 1})
+	s.FieldSets = []mapping.FieldSet{{versioned.MustFieldByName("Name")}}
+	ReturnDeleted(s)
+	q, err = p.parseDeleteQuery(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, "DELETE FROM public.versioned_models WHERE (id, version) IN (($1,$2)) RETURNING name, id", q.query)
+}
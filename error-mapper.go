@@ -49,7 +49,9 @@ var pqMapping = map[string]error{
 	"3F000": query.ErrInternal,
 
 	// Class 40 - Transaction Rollback
-	"40": query.ErrTxState,
+	"40":    query.ErrTxState,
+	"40001": ErrSerializationFailure,
+	"40P01": ErrDeadlockDetected,
 
 	// Class 42 - Invalid Syntax
 	"42":    query.ErrInternal,
@@ -69,6 +71,9 @@ var pqMapping = map[string]error{
 	// Class 54 - Program Limit Exceeded
 	"54": repository.ErrRepository,
 
+	// Class 55 - Object Not In Prerequisite State
+	"55P03": ErrLockNotAvailable,
+
 	// Class 58 - System Errors
 	"58": repository.ErrRepository,
 
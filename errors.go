@@ -1,7 +1,12 @@
 package postgres
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
 )
 
 var (
@@ -13,4 +18,40 @@ var (
 
 	// ErrInternal is the internal error in the postgres repository package.
 	ErrInternal = errors.Wrap(errors.ErrInternal, "postgres")
+
+	// ErrStaleModel is the error classification for the models with the optimistic locking version field
+	// that were concurrently modified or deleted.
+	ErrStaleModel = errors.Wrap(ErrPostgres, "stale model")
+
+	// ErrSerializationFailure is the error classification for the transactions that could not be serialized
+	// with the concurrent transactions. The transaction could be retried.
+	ErrSerializationFailure = errors.Wrap(query.ErrTxState, "serialization failure")
+
+	// ErrDeadlockDetected is the error classification for the transactions aborted due to the deadlock.
+	// The transaction could be retried.
+	ErrDeadlockDetected = errors.Wrap(query.ErrTxState, "deadlock detected")
+
+	// ErrLockNotAvailable is the error classification for the rows that could not be locked without waiting.
+	ErrLockNotAvailable = errors.Wrap(query.ErrTransaction, "lock not available")
 )
+
+// StaleModelsError is the error returned when the models with the optimistic locking version field were
+// concurrently modified or deleted. It matches the ErrStaleModel classification.
+type StaleModelsError struct {
+	// Models are the stale models that were not updated nor deleted.
+	Models []mapping.Model
+}
+
+// Error implements error interface.
+func (e *StaleModelsError) Error() string {
+	keys := make([]string, len(e.Models))
+	for i, model := range e.Models {
+		keys[i] = fmt.Sprintf("%v", model.GetPrimaryKeyValue())
+	}
+	return fmt.Sprintf("stale models with primary keys: [%s] were modified or deleted", strings.Join(keys, ","))
+}
+
+// Unwrap implements the errors unwrapper interface.
+func (e *StaleModelsError) Unwrap() error {
+	return ErrStaleModel
+}
@@ -61,6 +61,20 @@ func InSQLizer(s *query.Scope, quotedWriter internal.QuotedWordWriteFunc, simple
 	b := &strings.Builder{}
 
 	quotedWriter(b, simple.StructField.DatabaseName)
+	// Large value lists are sent as a single array parameter, so that the statement doesn't exceed
+	// the parameters limit.
+	if len(simple.Values) > internal.InArrayThreshold {
+		if array, ok := internal.ArrayValue(simple.Values); ok {
+			if simple.Operator == filter.OpNotIn {
+				b.WriteString(" <> ALL(")
+			} else {
+				b.WriteString(" = ANY(")
+			}
+			b.WriteString(internal.StringIncrementor(s))
+			b.WriteRune(')')
+			return SQLQueries{SQLQuery{Query: b.String(), Values: []interface{}{array}}}, nil
+		}
+	}
 	b.WriteString(" ")
 	b.WriteString(op)
 	b.WriteString(" (")
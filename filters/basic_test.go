@@ -79,6 +79,41 @@ func TestInSQLizer(t *testing.T) {
 			assert.Equal(t, 6789, queries[0].Values[1])
 		}
 	})
+
+	t.Run("Array", func(t *testing.T) {
+		s := getScope(t)
+		values := make([]interface{}, internal.InArrayThreshold+1)
+		for i := range values {
+			values[i] = i
+		}
+		f := filter.New(s.ModelStruct.Primary(), filter.OpIn, values...)
+
+		queries, err := InSQLizer(s, internal.DummyQuotedWriteFunc, f)
+		require.NoError(t, err)
+
+		require.Len(t, queries, 1)
+		assert.Equal(t, "id = ANY($1)", queries[0].Query)
+		if assert.Len(t, queries[0].Values, 1) {
+			array, ok := queries[0].Values[0].([]int64)
+			require.True(t, ok)
+			assert.Len(t, array, len(values))
+		}
+	})
+
+	t.Run("NotInArray", func(t *testing.T) {
+		s := getScope(t)
+		values := make([]interface{}, internal.InArrayThreshold+1)
+		for i := range values {
+			values[i] = i
+		}
+		f := filter.New(s.ModelStruct.Primary(), filter.OpNotIn, values...)
+
+		queries, err := InSQLizer(s, internal.DummyQuotedWriteFunc, f)
+		require.NoError(t, err)
+
+		require.Len(t, queries, 1)
+		assert.Equal(t, "id <> ALL($1)", queries[0].Query)
+	})
 }
 
 // TestStringOperatorsSQLizer test the string value sqlizers
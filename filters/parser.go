@@ -12,15 +12,31 @@ import (
 )
 
 // ParseFilters parses the filters into SQLQueries for the provided scope.
+// The filters of unsupported types are omitted.
 func ParseFilters(s *query.Scope, writer internal.QuotedWordWriteFunc) (SQLQueries, error) {
+	return parseFilters(s, writer, s.Filters, false)
+}
+
+// ParseFiltersStrict parses the filters into SQLQueries for the provided scope.
+// If any of the scope's filters is of unsupported type the function returns filter.ErrFilterFormat error.
+// The filters that would be omitted, i.e. the ones on the fields with the db:"-" tag or the relation filters without
+// any nested filters, would widen the query, thus these results in the filter.ErrFilterField error, unless the scope
+// allows the full table queries.
+func ParseFiltersStrict(s *query.Scope, writer internal.QuotedWordWriteFunc) (SQLQueries, error) {
+	return parseFilters(s, writer, s.Filters, true)
+}
+
+func parseFilters(s *query.Scope, writer internal.QuotedWordWriteFunc, filters []filter.Filter, strict bool) (SQLQueries, error) {
 	queries := SQLQueries{}
 
 	// at first get primary filters
-	for _, scopeFilter := range s.Filters {
+	for _, scopeFilter := range filters {
 		switch ft := scopeFilter.(type) {
 		case filter.Simple:
 			if ft.StructField.DatabaseSkip() {
-				log.Debug2f("Skipping foreign key filter with db:\"-\" omit option")
+				if err := omitFilter(s, ft, strict, "field with db:\"-\" omit option"); err != nil {
+					return nil, err
+				}
 				continue
 			}
 			sqlizer, err := getOperatorSQLizer(ft.Operator)
@@ -41,7 +57,9 @@ func ParseFilters(s *query.Scope, writer internal.QuotedWordWriteFunc) (SQLQueri
 			)
 			for _, elem := range ft {
 				if elem.StructField.DatabaseSkip() {
-					log.Debug2f("Skipping foreign key filter with db:\"-\" omit option")
+					if err := omitFilter(s, elem, strict, "field with db:\"-\" omit option"); err != nil {
+						return nil, err
+					}
 					continue
 				}
 				sqlizer, err := getOperatorSQLizer(elem.Operator)
@@ -90,9 +108,38 @@ func ParseFilters(s *query.Scope, writer internal.QuotedWordWriteFunc) (SQLQueri
 				// 0 or 1 filter.
 				queries = append(queries, orQueries...)
 			}
+		case filter.Relation:
+			subQueries, err := relationSQLizer(s, writer, ft, strict)
+			if err != nil {
+				return nil, err
+			}
+			queries = append(queries, subQueries...)
 		default:
+			if strict {
+				return nil, errors.WrapDetf(filter.ErrFilterFormat, "unsupported filter type: '%T'", scopeFilter)
+			}
+			log.Debug2f("Skipping unsupported filter type: '%T'", scopeFilter)
 			continue
 		}
 	}
 	return queries, nil
 }
+
+// omitFilter checks if the filter 'f' could be omitted from the query. In the strict mode the omitted filter would
+// widen the query, thus an error is returned unless the scope allows the full table queries.
+func omitFilter(s *query.Scope, f filter.Filter, strict bool, reason string) error {
+	if strict && !isFullTableAllowed(s) {
+		return errors.WrapDetf(filter.ErrFilterField, "filter: '%s' would be omitted - %s", f, reason)
+	}
+	log.Debug2f("Skipping filter: '%s' - %s", f, reason)
+	return nil
+}
+
+func isFullTableAllowed(s *query.Scope) bool {
+	v, ok := s.StoreGet(internal.FullTableKey)
+	if !ok {
+		return false
+	}
+	allowed, _ := v.(bool)
+	return allowed
+}
@@ -0,0 +1,104 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+)
+
+type unsupportedFilter struct{}
+
+func (u unsupportedFilter) Copy() filter.Filter {
+	return u
+}
+
+func (u unsupportedFilter) String() string {
+	return "unsupported"
+}
+
+// TestParseFiltersStrict tests the strict filters parsing.
+func TestParseFiltersStrict(t *testing.T) {
+	s := getScope(t)
+	s.Filters = filter.Filters{filter.New(s.ModelStruct.Primary(), filter.OpEqual, 1), unsupportedFilter{}}
+
+	q, err := ParseFilters(s, internal.DummyQuotedWriteFunc)
+	require.NoError(t, err)
+	assert.Len(t, q, 1)
+
+	_, err = ParseFiltersStrict(s, internal.DummyQuotedWriteFunc)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, filter.ErrFilterFormat))
+	assert.Contains(t, err.Error(), "unsupportedFilter")
+}
+
+// TestParseFiltersStrictOmitted tests the strict parsing of the filters that would be omitted.
+func TestParseFiltersStrictOmitted(t *testing.T) {
+	m := getRelationModelMap(t)
+	require.NoError(t, m.RegisterModels(&tests.OmitModel{}))
+	omitModel, ok := m.GetModelStruct(&tests.OmitModel{})
+	require.True(t, ok)
+	require.NoError(t, migrate.PrepareModels(omitModel))
+
+	blog, ok := m.GetModelStruct(&tests.Blog{})
+	require.True(t, ok)
+	posts, ok := blog.RelationByName("Posts")
+	require.True(t, ok)
+	title := blog.MustFieldByName("Title")
+
+	t.Run("EmptyRelation", func(t *testing.T) {
+		s := query.NewScope(blog)
+		s.Filters = filter.Filters{filter.New(title, filter.OpEqual, "title"), filter.NewRelation(posts)}
+
+		q, err := ParseFilters(s, internal.DummyQuotedWriteFunc)
+		require.NoError(t, err)
+		require.Len(t, q, 2)
+		assert.Equal(t, "id IN (SELECT blog_id FROM public.posts)", q[1].Query)
+
+		// The unfiltered sub query would widen the query.
+		_, err = ParseFiltersStrict(s, internal.DummyQuotedWriteFunc)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, filter.ErrFilterField))
+	})
+
+	t.Run("OmittedNested", func(t *testing.T) {
+		s := query.NewScope(blog)
+		s.Filters = filter.Filters{
+			filter.New(title, filter.OpEqual, "title"),
+			filter.NewRelation(posts, filter.New(omitModel.MustFieldByName("OmitField"), filter.OpEqual, "omitted")),
+		}
+		_, err := ParseFiltersStrict(s, internal.DummyQuotedWriteFunc)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, filter.ErrFilterField))
+	})
+
+	t.Run("PartiallyOmitted", func(t *testing.T) {
+		newScope := func() *query.Scope {
+			s := query.NewScope(omitModel)
+			s.Filters = filter.Filters{
+				filter.New(omitModel.Primary(), filter.OpEqual, 1),
+				filter.New(omitModel.MustFieldByName("OmitField"), filter.OpEqual, "omitted"),
+			}
+			return s
+		}
+		_, err := ParseFiltersStrict(newScope(), internal.DummyQuotedWriteFunc)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, filter.ErrFilterField))
+
+		// The full table queries allows to omit the filters.
+		s := newScope()
+		s.StoreSet(internal.FullTableKey, true)
+		q, err := ParseFiltersStrict(s, internal.DummyQuotedWriteFunc)
+		require.NoError(t, err)
+		require.Len(t, q, 1)
+		assert.Equal(t, "id = $1", q[0].Query)
+	})
+}
@@ -0,0 +1,134 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+)
+
+// RelationSQLizer creates the SQLQueries for the relationship filter. The nested filters are parsed into
+// the sub query on the related model table, which is then matched with the root model using relationship foreign keys.
+// I.e. for the belongs to relationship:
+//
+//	foreign_key IN (SELECT id FROM schema.related WHERE nested_filters)
+//
+// for the has one and has many relationship:
+//
+//	id IN (SELECT foreign_key FROM schema.related WHERE nested_filters)
+//
+// for the many to many relationship:
+//
+//	id IN (SELECT foreign_key FROM schema.join WHERE mtm_foreign_key IN (SELECT id FROM schema.related WHERE nested_filters))
+//
+// The sub queries of the soft deletable related models filter out the deleted rows - 'deleted_at IS NULL',
+// unless the scope has the IncludeDeleted option set.
+func RelationSQLizer(s *query.Scope, quotedWriter internal.QuotedWordWriteFunc, relation filter.Relation) (SQLQueries, error) {
+	return relationSQLizer(s, quotedWriter, relation, false)
+}
+
+func relationSQLizer(s *query.Scope, quotedWriter internal.QuotedWordWriteFunc, relation filter.Relation, strict bool) (SQLQueries, error) {
+	if relation.StructField == nil || !relation.StructField.IsRelationship() {
+		return nil, errors.WrapDet(filter.ErrFilterField, "provided relation filter with non relationship field")
+	}
+	rel := relation.StructField.Relationship()
+	relatedModel := rel.RelatedModelStruct()
+
+	b := &strings.Builder{}
+	switch rel.Kind() {
+	case mapping.RelBelongsTo:
+		quotedWriter(b, rel.ForeignKey().DatabaseName)
+		b.WriteString(" IN (SELECT ")
+		quotedWriter(b, relatedModel.Primary().DatabaseName)
+	case mapping.RelHasOne, mapping.RelHasMany:
+		quotedWriter(b, relation.StructField.ModelStruct().Primary().DatabaseName)
+		b.WriteString(" IN (SELECT ")
+		quotedWriter(b, rel.ForeignKey().DatabaseName)
+	case mapping.RelMany2Many:
+		quotedWriter(b, relation.StructField.ModelStruct().Primary().DatabaseName)
+		b.WriteString(" IN (SELECT ")
+		quotedWriter(b, rel.ForeignKey().DatabaseName)
+		b.WriteString(" FROM ")
+		writeTableName(b, quotedWriter, rel.JoinModel())
+		b.WriteString(" WHERE ")
+		quotedWriter(b, rel.ManyToManyForeignKey().DatabaseName)
+		b.WriteString(" IN (SELECT ")
+		quotedWriter(b, relatedModel.Primary().DatabaseName)
+	default:
+		return nil, errors.WrapDetf(filter.ErrFilterFormat, "unsupported relationship kind: '%s' for the relation filter: '%s'", rel.Kind(), relation.StructField)
+	}
+	b.WriteString(" FROM ")
+	writeTableName(b, quotedWriter, relatedModel)
+
+	nestedQueries, err := parseFilters(s, quotedWriter, relation.Nested, strict)
+	if err != nil {
+		return nil, err
+	}
+	// The sub query without nested filters matches all the related rows, which would widen the strict query.
+	if strict && len(nestedQueries) == 0 && !isFullTableAllowed(s) {
+		return nil, errors.WrapDetf(filter.ErrFilterField, "relation filter: '%s' has no nested filters", relation.StructField)
+	}
+	if deletedAt, ok := softDeletedField(s, relatedModel, relation.Nested); ok {
+		sb := &strings.Builder{}
+		quotedWriter(sb, deletedAt.DatabaseName)
+		sb.WriteString(" IS NULL")
+		nestedQueries = append(nestedQueries, SQLQuery{Query: sb.String()})
+	}
+	q := SQLQuery{}
+	if len(nestedQueries) > 0 {
+		b.WriteString(" WHERE ")
+		for i, nested := range nestedQueries {
+			b.WriteString(nested.Query)
+			if i < len(nestedQueries)-1 {
+				b.WriteString(" AND ")
+			}
+			q.Values = append(q.Values, nested.Values...)
+		}
+	}
+	b.WriteRune(')')
+	if rel.Kind() == mapping.RelMany2Many {
+		b.WriteRune(')')
+	}
+	q.Query = b.String()
+	return SQLQueries{q}, nil
+}
+
+// softDeletedField gets the DeletedAt field of the soft deletable 'related' model, which sub query needs to filter out
+// the deleted rows. The field is not returned if the scope has the IncludeDeleted option set or the 'nested' filters
+// already filter the DeletedAt field.
+func softDeletedField(s *query.Scope, related *mapping.ModelStruct, nested filter.Filters) (*mapping.StructField, bool) {
+	deletedAt, ok := related.DeletedAt()
+	if !ok {
+		return nil, false
+	}
+	if v, ok := s.StoreGet(internal.IncludeDeletedKey); ok {
+		if includeDeleted, _ := v.(bool); includeDeleted {
+			return nil, false
+		}
+	}
+	for _, f := range nested {
+		switch ft := f.(type) {
+		case filter.Simple:
+			if ft.StructField == deletedAt {
+				return nil, false
+			}
+		case filter.OrGroup:
+			for _, simple := range ft {
+				if simple.StructField == deletedAt {
+					return nil, false
+				}
+			}
+		}
+	}
+	return deletedAt, true
+}
+
+func writeTableName(b *strings.Builder, quotedWriter internal.QuotedWordWriteFunc, model *mapping.ModelStruct) {
+	quotedWriter(b, model.DatabaseSchemaName)
+	b.WriteRune('.')
+	quotedWriter(b, model.DatabaseName)
+}
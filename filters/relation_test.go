@@ -0,0 +1,118 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+)
+
+func getRelationModelMap(t *testing.T) *mapping.ModelMap {
+	t.Helper()
+	m := mapping.NewModelMap(mapping.WithNamingConvention(mapping.SnakeCase))
+	err := m.RegisterModels(&tests.Blog{}, &tests.Post{}, &tests.Comment{}, &tests.ManyToManyModel{}, &tests.JoinModel{}, &tests.RelatedModel{})
+	require.NoError(t, err)
+	require.NoError(t, migrate.PrepareModels(m.Models()...))
+	return m
+}
+
+// TestRelationSQLizer tests the relationship filter parser.
+func TestRelationSQLizer(t *testing.T) {
+	m := getRelationModelMap(t)
+
+	t.Run("BelongsTo", func(t *testing.T) {
+		mStruct, ok := m.GetModelStruct(&tests.Blog{})
+		require.True(t, ok)
+		relation, ok := mStruct.RelationByName("CurrentPost")
+		require.True(t, ok)
+		post := relation.Relationship().RelatedModelStruct()
+
+		s := query.NewScope(mStruct)
+		s.Filters = filter.Filters{
+			filter.New(mStruct.MustFieldByName("Title"), filter.OpEqual, "title"),
+			filter.NewRelation(relation, filter.New(post.MustFieldByName("Body"), filter.OpContains, "body")),
+		}
+		q, err := ParseFilters(s, internal.DummyQuotedWriteFunc)
+		require.NoError(t, err)
+		require.Len(t, q, 2)
+		assert.Equal(t, "title = $1", q[0].Query)
+		assert.Equal(t, "current_post_id IN (SELECT id FROM public.posts WHERE body LIKE $2)", q[1].Query)
+		assert.Equal(t, []interface{}{"%body%"}, q[1].Values)
+	})
+
+	t.Run("HasOne", func(t *testing.T) {
+		mStruct, ok := m.GetModelStruct(&tests.Post{})
+		require.True(t, ok)
+		relation, ok := mStruct.RelationByName("LatestComment")
+		require.True(t, ok)
+		comment := relation.Relationship().RelatedModelStruct()
+
+		s := query.NewScope(mStruct)
+		s.Filters = filter.Filters{filter.NewRelation(relation, filter.New(comment.MustFieldByName("Body"), filter.OpEqual, "body"))}
+		q, err := ParseFilters(s, internal.DummyQuotedWriteFunc)
+		require.NoError(t, err)
+		require.Len(t, q, 1)
+		assert.Equal(t, "id IN (SELECT post_id FROM public.comments WHERE body = $1)", q[0].Query)
+		assert.Equal(t, []interface{}{"body"}, q[0].Values)
+	})
+
+	t.Run("HasMany", func(t *testing.T) {
+		mStruct, ok := m.GetModelStruct(&tests.Blog{})
+		require.True(t, ok)
+		relation, ok := mStruct.RelationByName("Posts")
+		require.True(t, ok)
+		post := relation.Relationship().RelatedModelStruct()
+
+		s := query.NewScope(mStruct)
+		s.Filters = filter.Filters{filter.NewRelation(relation,
+			filter.New(post.MustFieldByName("Title"), filter.OpIn, "first", "second"),
+			filter.Or(filter.New(post.MustFieldByName("Body"), filter.OpIsNull), filter.New(post.MustFieldByName("Body"), filter.OpEqual, "")),
+		)}
+		q, err := ParseFilters(s, internal.DummyQuotedWriteFunc)
+		require.NoError(t, err)
+		require.Len(t, q, 1)
+		assert.Equal(t, "id IN (SELECT blog_id FROM public.posts WHERE title IN ($1,$2) AND (body IS NULL OR body = $3))", q[0].Query)
+		assert.Equal(t, []interface{}{"first", "second", ""}, q[0].Values)
+	})
+
+	t.Run("Many2Many", func(t *testing.T) {
+		mStruct, ok := m.GetModelStruct(&tests.ManyToManyModel{})
+		require.True(t, ok)
+		relation, ok := mStruct.RelationByName("Many2Many")
+		require.True(t, ok)
+		related := relation.Relationship().RelatedModelStruct()
+
+		s := query.NewScope(mStruct)
+		s.Filters = filter.Filters{filter.NewRelation(relation, filter.New(related.MustFieldByName("FloatField"), filter.OpGreaterThan, 1.5))}
+		q, err := ParseFilters(s, internal.DummyQuotedWriteFunc)
+		require.NoError(t, err)
+		require.Len(t, q, 1)
+		assert.Equal(t, "id IN (SELECT foreign_key FROM public.join_models WHERE mt_m_foreign_key IN (SELECT id FROM public.related_models WHERE float_field > $1))", q[0].Query)
+		assert.Equal(t, []interface{}{1.5}, q[0].Values)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		mStruct, ok := m.GetModelStruct(&tests.Blog{})
+		require.True(t, ok)
+		posts, ok := mStruct.RelationByName("Posts")
+		require.True(t, ok)
+		comments, ok := posts.Relationship().RelatedModelStruct().RelationByName("Comments")
+		require.True(t, ok)
+		comment := comments.Relationship().RelatedModelStruct()
+
+		s := query.NewScope(mStruct)
+		s.Filters = filter.Filters{filter.NewRelation(posts, filter.NewRelation(comments, filter.New(comment.MustFieldByName("Body"), filter.OpEqual, "body")))}
+		q, err := ParseFilters(s, internal.DummyQuotedWriteFunc)
+		require.NoError(t, err)
+		require.Len(t, q, 1)
+		assert.Equal(t, "id IN (SELECT blog_id FROM public.posts WHERE id IN (SELECT post_id FROM public.comments WHERE body = $1))", q[0].Query)
+	})
+}
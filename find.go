@@ -16,7 +16,9 @@ import (
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
 )
 
-// Find lists all the values that matches scope's filters, sorts and pagination.
+// Find lists all the values that matches scope's filters, sorts and pagination. The scope's included relations
+// are loaded along with the models, and only the ones that could not be loaded are left in the scope's
+// IncludedRelations, so that these are found by the neuron.
 // Implements repository.Repository interface.
 func (p *Postgres) Find(ctx context.Context, s *query.Scope) error {
 	q, err := p.parseSelectQuery(s)
@@ -34,67 +36,115 @@ func (p *Postgres) Find(ctx context.Context, s *query.Scope) error {
 	}()
 
 	for rows.Next() {
-		if err := p.scanRow(s, q, rows); err != nil {
+		if len(q.joined) > 0 {
+			err = p.scanJoinedRow(s, q, rows)
+		} else {
+			err = p.scanRow(s, q, rows)
+		}
+		if err != nil {
 			return errors.Wrapf(p.neuronError(err), "scanning row failed: %v", err)
 		}
 	}
+	if err = rows.Err(); err != nil {
+		return p.neuronError(err)
+	}
+	rows.Close()
+
+	if q.cursor != nil {
+		if err = setPageCursors(s, q); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Models) > 0 {
+		for _, included := range q.batched {
+			if err = p.findBatchedRelation(ctx, s, included); err != nil {
+				return err
+			}
+		}
+	}
+	// The eagerly loaded relations are already set in the models - leave only the ones that needs to be found
+	// by the neuron.
+	s.IncludedRelations = q.remaining
 	return nil
 }
 
 func (p *Postgres) scanRow(s *query.Scope, q *selectQuery, rows pgx.Rows) (err error) {
-	model := mapping.NewModel(s.ModelStruct)
-	var (
-		fieldValues  []interface{}
-		fieldValue   interface{}
-		timePointers []int
-	)
+	scanner, err := newModelScanner(s.ModelStruct, q.fieldsOrder)
+	if err != nil {
+		return err
+	}
+	// Scan models value.
+	if err := rows.Scan(scanner.values...); err != nil {
+		return err
+	}
+	if err = scanner.setTimePointers(); err != nil {
+		return err
+	}
+	s.Models = append(s.Models, scanner.model)
+	return nil
+}
+
+// modelScanner prepares the scan destinations for the model fields.
+type modelScanner struct {
+	model        mapping.Model
+	fielder      mapping.Fielder
+	fields       []*mapping.StructField
+	values       []interface{}
+	timePointers []int
+}
+
+func newModelScanner(mStruct *mapping.ModelStruct, fields []*mapping.StructField) (*modelScanner, error) {
+	return newModelFieldsScanner(mapping.NewModel(mStruct), fields)
+}
+
+// newModelFieldsScanner creates the scanner that scans the 'fields' values into an existing 'model'.
+func newModelFieldsScanner(model mapping.Model, fields []*mapping.StructField) (*modelScanner, error) {
 	fielder, ok := model.(mapping.Fielder)
 	if !ok {
-		return errors.Wrapf(mapping.ErrModelNotImplements, "Model: '%s' doesn't implement Fielder interface", s.ModelStruct)
+		return nil, errors.Wrapf(mapping.ErrModelNotImplements, "Model: '%T' doesn't implement Fielder interface", model)
 	}
+	m := &modelScanner{model: model, fielder: fielder, fields: fields}
 
 	// get the field values with the provided order
-	for i, field := range q.fieldsOrder {
+	for i, field := range fields {
 		if field.IsTimePointer() {
 			if log.Level() == log.LevelDebug3 {
 				log.Debug3f("scanned Field: '%s' isTimePointer", field.Name())
 			}
-			timePointers = append(timePointers, i)
-			fieldValues = append(fieldValues, &pgtype.Timestamp{})
+			m.timePointers = append(m.timePointers, i)
+			m.values = append(m.values, &pgtype.Timestamp{})
 		} else {
 			if log.Level() == log.LevelDebug3 {
 				log.Debug3f("scanned Field: '%s'", field.ReflectField().Type)
 			}
-			fieldValue, err = fielder.GetFieldsAddress(field)
+			fieldValue, err := fielder.GetFieldsAddress(field)
 			if err != nil {
-				return err
+				return nil, err
 			}
-			fieldValues = append(fieldValues, fieldValue)
+			m.values = append(m.values, fieldValue)
 		}
 	}
+	return m, nil
+}
 
-	// Scan models value.
-	if err := rows.Scan(fieldValues...); err != nil {
-		return err
-	}
-
-	// Set time pointers.
-	for _, index := range timePointers {
-		nt, ok := fieldValues[index].(*pgtype.Timestamp)
+// setTimePointers sets the scanned time pointer values into the model.
+func (m *modelScanner) setTimePointers() (err error) {
+	for _, index := range m.timePointers {
+		nt, ok := m.values[index].(*pgtype.Timestamp)
 		if !ok {
 			log.Errorf("Getting NullTime failed. ")
 			continue
 		}
 		if nt.Status != pgtype.Null {
-			err = fielder.SetFieldValue(q.fieldsOrder[index], nt.Time)
+			err = m.fielder.SetFieldValue(m.fields[index], nt.Time)
 		} else {
-			err = fielder.SetFieldZeroValue(q.fieldsOrder[index])
+			err = m.fielder.SetFieldZeroValue(m.fields[index])
 		}
 		if err != nil {
 			return err
 		}
 	}
-	s.Models = append(s.Models, model)
 	return nil
 }
 
@@ -102,6 +152,18 @@ type selectQuery struct {
 	query       string
 	values      []interface{}
 	fieldsOrder []*mapping.StructField
+	// extraFields are the fields selected by the root sub query of the joined select query, which are not scanned.
+	extraFields []*mapping.StructField
+	// joined are the included relations loaded within the select query.
+	joined []*joinedRelation
+	// batched are the included relations loaded with a single query after the root models are found.
+	batched []*query.IncludedRelation
+	// remaining are the included relations that could not be loaded by the repository.
+	remaining []*query.IncludedRelation
+	// sorts are the sorting order of the query.
+	sorts []query.Sort
+	// cursor is the keyset pagination of the query.
+	cursor *cursorPagination
 }
 
 func (p *Postgres) parseSelectQuery(s *query.Scope) (*selectQuery, error) {
@@ -110,34 +172,33 @@ func (p *Postgres) parseSelectQuery(s *query.Scope) (*selectQuery, error) {
 		return nil, errors.Wrap(query.ErrNoFieldsInFieldSet, "no fieldset found for the list/get type query")
 	}
 
-	var fields string
 	q := &selectQuery{}
-	sb := &strings.Builder{}
-
-	var i int
 	for _, field := range commonFieldSet {
 		if field.DatabaseSkip() {
 			continue
 		}
-
 		q.fieldsOrder = append(q.fieldsOrder, field)
-		p.writeQuotedWord(sb, field.DatabaseName)
-		if i != len(commonFieldSet)-1 {
-			sb.WriteString(", ")
-		}
-		i++
 	}
-	fields = sb.String()
-	if fields == "" {
+	if len(q.fieldsOrder) == 0 {
 		// All the fields had to be omitted.
 		return nil, errors.Wrap(query.ErrNoFieldsInFieldSet, "provided empty fieldset for the list/get query")
 	}
-	sb.Reset()
+	q.sorts = s.SortingOrder
+	if err := prepareCursorPagination(s, q); err != nil {
+		return nil, err
+	}
+	p.prepareIncludedRelations(s, q)
 
+	sb := &strings.Builder{}
 	mStruct := s.ModelStruct
 	// Prepare the select query for given fields.
 	sb.WriteString("SELECT ")
-	sb.WriteString(fields)
+	for i, field := range append(q.fieldsOrder[:len(q.fieldsOrder):len(q.fieldsOrder)], q.extraFields...) {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		p.writeQuotedWord(sb, field.DatabaseName)
+	}
 	sb.WriteString(" FROM ")
 	p.writeQuotedWord(sb, mStruct.DatabaseSchemaName)
 	sb.WriteRune('.')
@@ -149,6 +210,13 @@ func (p *Postgres) parseSelectQuery(s *query.Scope) (*selectQuery, error) {
 		return nil, err
 	}
 
+	if softDeleted, ok := p.softDeleteFilter(s); ok {
+		parsedFilters = append(parsedFilters, softDeleted)
+	}
+	if q.cursor != nil && q.cursor.values != nil {
+		parsedFilters = append(parsedFilters, p.parseSelectCursor(s, q))
+	}
+
 	if len(parsedFilters) > 0 {
 		sb.WriteString(" WHERE ")
 		for i, f := range parsedFilters {
@@ -160,7 +228,7 @@ func (p *Postgres) parseSelectQuery(s *query.Scope) (*selectQuery, error) {
 		}
 	}
 
-	err = p.parseSelectSort(s, sb)
+	err = p.parseSelectSort(s, q.sorts, sb, "")
 	if err != nil {
 		return nil, err
 	}
@@ -169,8 +237,17 @@ func (p *Postgres) parseSelectQuery(s *query.Scope) (*selectQuery, error) {
 	if paginationValues != nil {
 		q.values = append(q.values, paginationValues...)
 	}
+	// The locking clause of the joined query is a part of the root sub query, so that only the root rows are locked.
+	if err = writeRowLock(s, sb); err != nil {
+		return nil, err
+	}
 
 	q.query = sb.String()
+	if len(q.joined) > 0 {
+		if q.query, err = p.joinedSelectQuery(s, q); err != nil {
+			return nil, err
+		}
+	}
 	return q, nil
 }
 
@@ -179,9 +256,14 @@ func parseSelectPagination(s *query.Scope, sb *strings.Builder) (values []interf
 		return values
 	}
 	if s.Pagination.Limit != 0 {
+		limit := s.Pagination.Limit
+		if isCursorPagination(s) {
+			// Select one more row in order to check if there is another page.
+			limit++
+		}
 		sb.WriteString(" LIMIT ")
 		sb.WriteString(internal.StringIncrementor(s))
-		values = append(values, s.Pagination.Limit)
+		values = append(values, limit)
 	}
 
 	if s.Pagination.Offset != 0 {
@@ -192,19 +274,24 @@ func parseSelectPagination(s *query.Scope, sb *strings.Builder) (values []interf
 	return values
 }
 
-func (p *Postgres) parseSelectSort(s *query.Scope, sb *strings.Builder) error {
+// parseSelectSort writes the 'sorts' sorting order. If the 'alias' is not empty, the sort columns are qualified with it.
+func (p *Postgres) parseSelectSort(s *query.Scope, sorts []query.Sort, sb *strings.Builder, alias string) error {
 	if log.Level() == log.LevelDebug3 {
-		log.Debug3f("[SCOPE][%s] sorting fields: %v", s.ID, s.SortingOrder)
+		log.Debug3f("[SCOPE][%s] sorting fields: %v", s.ID, sorts)
 	}
-	if len(s.SortingOrder) == 0 {
+	if len(sorts) == 0 {
 		return nil
 	}
 
 	sb.WriteString(" ORDER BY ")
-	for i, field := range s.SortingOrder {
+	for i, field := range sorts {
 		if log.Level() == log.LevelDebug3 {
 			log.Debug3f("Sorting by field: '%s' with '%s' order", field.Field().NeuronName(), field.Order().String())
 		}
+		if alias != "" {
+			sb.WriteString(alias)
+			sb.WriteRune('.')
+		}
 		p.writeQuotedWord(sb, field.Field().DatabaseName)
 
 		if field.Order() == query.DescendingOrder {
@@ -214,7 +301,7 @@ func (p *Postgres) parseSelectSort(s *query.Scope, sb *strings.Builder) error {
 			log.Debug2f("[SCOPE][%s] ascending sorting by: '%s' at: '%d' sort order", s.ID, field.Field().DatabaseName, i)
 			sb.WriteString(" ASC")
 		}
-		if i != len(s.SortingOrder)-1 {
+		if i != len(sorts)-1 {
 			sb.WriteString(", ")
 		}
 	}
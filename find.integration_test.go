@@ -12,6 +12,8 @@ import (
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
 	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
 )
 
 // TestRepositoryFind tests the repository list method.
@@ -175,3 +177,58 @@ func TestRepositoryFind(t *testing.T) {
 // 		}
 // 	})
 // }
+
+// TestFindIncluded tests the relations included by the repository within the neuron's find query.
+func TestFindIncluded(t *testing.T) {
+	c := testingController(t, true, &tests.Blog{}, &tests.Post{}, &tests.Comment{})
+	p := testingRepository(c)
+
+	ctx := context.Background()
+	blog, err := c.ModelStruct(&tests.Blog{})
+	require.NoError(t, err)
+	post, err := c.ModelStruct(&tests.Post{})
+	require.NoError(t, err)
+	comment, err := c.ModelStruct(&tests.Comment{})
+	require.NoError(t, err)
+
+	defer func() {
+		for _, mStruct := range []*mapping.ModelStruct{comment, post, blog} {
+			_ = internal.DropTables(ctx, p.ConnPool, mStruct.DatabaseName, mStruct.DatabaseSchemaName)
+		}
+	}()
+	// The blogs and posts references each other - drop the blog's foreign key so that these could be inserted.
+	_, err = p.ConnPool.Exec(ctx, "ALTER TABLE public.blogs DROP CONSTRAINT fk_blogs_current_post_id")
+	require.NoError(t, err)
+
+	db := database.New(c)
+	b := &tests.Blog{Title: "blog", CurrentPostID: 1}
+	require.NoError(t, db.Query(blog, b).Insert())
+	first, second := &tests.Post{BlogID: b.ID, Title: "first"}, &tests.Post{BlogID: b.ID, Title: "second"}
+	require.NoError(t, db.Query(post, first, second).Insert())
+	comments := []mapping.Model{&tests.Comment{PostID: first.ID, Body: "a"}, &tests.Comment{PostID: first.ID, Body: "b"}, &tests.Comment{PostID: second.ID, Body: "c"}}
+	require.NoError(t, db.Query(comment, comments...).Insert())
+
+	models, err := db.Query(post).
+		Include(post.MustFieldByName("Comments")).
+		Include(post.MustFieldByName("LatestComment")).
+		OrderBy(query.SortField{StructField: post.Primary(), SortOrder: query.AscendingOrder}).
+		Find()
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+
+	// The relations loaded by the repository are not found once again by the neuron.
+	firstFound := models[0].(*tests.Post)
+	if assert.Len(t, firstFound.Comments, 2) {
+		ids := []interface{}{firstFound.Comments[0].ID, firstFound.Comments[1].ID}
+		assert.ElementsMatch(t, []interface{}{comments[0].GetPrimaryKeyValue(), comments[1].GetPrimaryKeyValue()}, ids)
+	}
+	// The has one relation is the related model with the lowest primary key.
+	if assert.NotNil(t, firstFound.LatestComment) {
+		assert.Equal(t, comments[0].GetPrimaryKeyValue(), firstFound.LatestComment.ID)
+	}
+	secondFound := models[1].(*tests.Post)
+	assert.Len(t, secondFound.Comments, 1)
+	if assert.NotNil(t, secondFound.LatestComment) {
+		assert.Equal(t, comments[2].GetPrimaryKeyValue(), secondFound.LatestComment.ID)
+	}
+}
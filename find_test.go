@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 	"github.com/neuronlabs/neuron/query/filter"
@@ -36,5 +37,128 @@ func TestParseSelect(t *testing.T) {
 	sq, err := repo.parseSelectQuery(s)
 	require.NoError(t, err)
 
-	assert.Equal(t, "SELECT id, attr_string, string_ptr, int, created_at, updated_at, deleted_at FROM public.models WHERE id IN ($1,$2) AND attr_string = $3 LIMIT $4 OFFSET $5", sq.query)
+	assert.Equal(t, "SELECT id, attr_string, string_ptr, int, created_at, updated_at, deleted_at FROM public.models WHERE id IN ($1,$2) AND attr_string = $3 AND deleted_at IS NULL LIMIT $4 OFFSET $5", sq.query)
+}
+
+func TestParseSelectIncluded(t *testing.T) {
+	c := testingController(t, false, &tests.Blog{}, &tests.Post{}, &tests.Comment{})
+	repo := testingRepository(c)
+
+	blog, err := c.ModelStruct(&tests.Blog{})
+	require.NoError(t, err)
+	post, err := c.ModelStruct(&tests.Post{})
+	require.NoError(t, err)
+
+	t.Run("BelongsTo", func(t *testing.T) {
+		currentPost, ok := blog.RelationByName("CurrentPost")
+		require.True(t, ok)
+		title, ok := blog.Attribute("title")
+		require.True(t, ok)
+
+		s := query.NewScope(blog)
+		s.FieldSets = []mapping.FieldSet{{blog.Primary(), title}}
+		s.IncludedRelations = []*query.IncludedRelation{{StructField: currentPost, Fieldset: mapping.FieldSet{post.MustFieldByName("Title")}}}
+		s.SortingOrder = []query.Sort{query.SortField{StructField: blog.MustFieldByName("ViewCount"), SortOrder: query.DescendingOrder}}
+		s.Pagination = &query.Pagination{Limit: 5}
+
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT t.id, t.title, j0.id, j0.title FROM (SELECT id, title, current_post_id, view_count FROM public.blogs ORDER BY view_count DESC LIMIT $1) AS t LEFT JOIN public.posts AS j0 ON j0.id = t.current_post_id ORDER BY t.view_count DESC", sq.query)
+		assert.Equal(t, []interface{}{int64(5)}, sq.values)
+		assert.Len(t, sq.joined, 1)
+		assert.Empty(t, sq.batched)
+		assert.Empty(t, sq.remaining)
+	})
+
+	t.Run("HasOne", func(t *testing.T) {
+		latestComment, ok := post.RelationByName("LatestComment")
+		require.True(t, ok)
+
+		s := query.NewScope(post)
+		s.FieldSets = []mapping.FieldSet{{post.MustFieldByName("Title")}}
+		s.IncludedRelations = []*query.IncludedRelation{{StructField: latestComment}}
+
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT t.title, j0.id, j0.post_id, j0.body FROM (SELECT title, id FROM public.posts) AS t LEFT JOIN LATERAL (SELECT id, post_id, body FROM public.comments WHERE post_id = t.id ORDER BY id LIMIT 1) AS j0 ON true", sq.query)
+	})
+
+	t.Run("HasMany", func(t *testing.T) {
+		posts, ok := blog.RelationByName("Posts")
+		require.True(t, ok)
+		title, ok := blog.Attribute("title")
+		require.True(t, ok)
+
+		s := query.NewScope(blog)
+		s.FieldSets = []mapping.FieldSet{{title}}
+		s.IncludedRelations = []*query.IncludedRelation{{StructField: posts}}
+
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		// The primary key is required to match the batched relation models.
+		assert.Equal(t, "SELECT title, id FROM public.blogs", sq.query)
+		assert.Empty(t, sq.joined)
+		assert.Len(t, sq.batched, 1)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		posts, ok := blog.RelationByName("Posts")
+		require.True(t, ok)
+		comments, ok := post.RelationByName("Comments")
+		require.True(t, ok)
+
+		s := query.NewScope(blog)
+		s.FieldSets = []mapping.FieldSet{{blog.Primary()}}
+		s.IncludedRelations = []*query.IncludedRelation{{StructField: posts, IncludedRelations: []*query.IncludedRelation{{StructField: comments}}}}
+
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		// Nested included relations are left for the neuron.
+		assert.Equal(t, "SELECT id FROM public.blogs", sq.query)
+		assert.Len(t, sq.remaining, 1)
+	})
+}
+
+func TestParseSelectRowLock(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	repo := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	newScope := func() *query.Scope {
+		s := query.NewScope(mStruct)
+		s.FieldSets = []mapping.FieldSet{{mStruct.Primary()}}
+		s.Pagination = &query.Pagination{Limit: 10}
+		return s
+	}
+
+	t.Run("NoTransaction", func(t *testing.T) {
+		s := newScope()
+		LockRows(s, RowLock{Strength: LockForUpdate})
+		_, err := repo.parseSelectQuery(s)
+		assert.True(t, errors.Is(err, query.ErrTxInvalid))
+	})
+
+	t.Run("SkipLocked", func(t *testing.T) {
+		s := newScope()
+		s.Transaction = &query.Transaction{}
+		LockRows(s, RowLock{Strength: LockForNoKeyUpdate, Wait: LockSkipLocked})
+		sq, err := repo.parseSelectQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "SELECT id FROM public.models WHERE deleted_at IS NULL LIMIT $1 FOR NO KEY UPDATE SKIP LOCKED", sq.query)
+	})
+
+	t.Run("InvalidStrength", func(t *testing.T) {
+		s := newScope()
+		s.Transaction = &query.Transaction{}
+		LockRows(s, RowLock{Wait: LockNoWait})
+		_, err := repo.parseSelectQuery(s)
+		assert.True(t, errors.Is(err, query.ErrInvalidParameter))
+	})
 }
@@ -0,0 +1,428 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+// rootAlias is the alias of the root model sub query in the joined select query.
+const rootAlias = "t"
+
+// joinedRelation is the included relation loaded within the root select query.
+type joinedRelation struct {
+	included *query.IncludedRelation
+	// fields are the related model fields selected in the join. The first field is always the related primary key.
+	fields []*mapping.StructField
+}
+
+// prepareIncludedRelations splits the scope's included relations into the ones loaded by the repository
+// and the remaining ones that needs to be found by the neuron. The belongs to and has one relations are joined
+// to the select query, where the has many and many to many relations are found in a single batched query.
+// The relation is loaded by the repository only if it doesn't have nested included relations and all of its
+// models are registered within given repository.
+func (p *Postgres) prepareIncludedRelations(s *query.Scope, q *selectQuery) {
+	for _, included := range s.IncludedRelations {
+		if !p.canLoadIncluded(included) {
+			q.remaining = append(q.remaining, included)
+			continue
+		}
+		switch included.StructField.Relationship().Kind() {
+		case mapping.RelBelongsTo, mapping.RelHasOne:
+			q.joined = append(q.joined, &joinedRelation{included: included, fields: includedFields(included)})
+		case mapping.RelHasMany, mapping.RelMany2Many:
+			q.batched = append(q.batched, included)
+		default:
+			q.remaining = append(q.remaining, included)
+		}
+	}
+
+	// The batched relations are matched by the root model primary key - it needs to be scanned.
+	if len(q.batched) > 0 && !containsField(q.fieldsOrder, s.ModelStruct.Primary()) {
+		q.fieldsOrder = append(q.fieldsOrder, s.ModelStruct.Primary())
+	}
+	if len(q.joined) == 0 {
+		return
+	}
+	// The root sub query needs to select all the fields used in the joins and outer sorting.
+	for _, joined := range q.joined {
+		relation := joined.included.StructField.Relationship()
+		if relation.Kind() == mapping.RelBelongsTo {
+			q.addExtraField(relation.ForeignKey())
+		} else {
+			q.addExtraField(s.ModelStruct.Primary())
+		}
+	}
+	for _, sort := range q.sorts {
+		q.addExtraField(sort.Field())
+	}
+}
+
+func (p *Postgres) canLoadIncluded(included *query.IncludedRelation) bool {
+	// Nested included relations are found by the neuron.
+	if len(included.IncludedRelations) != 0 {
+		return false
+	}
+	relation := included.StructField.Relationship()
+	if !p.isModelRegistered(relation.RelatedModelStruct()) {
+		return false
+	}
+	if relation.Kind() == mapping.RelMany2Many && !p.isModelRegistered(relation.JoinModel()) {
+		return false
+	}
+	return true
+}
+
+func (q *selectQuery) addExtraField(field *mapping.StructField) {
+	if containsField(q.fieldsOrder, field) || containsField(q.extraFields, field) {
+		return
+	}
+	q.extraFields = append(q.extraFields, field)
+}
+
+// joinedSelectQuery wraps the root select query in a sub query and joins all included belongs to and has one relations.
+//
+//	SELECT t.id, t.title, j0.id, j0.title FROM (root_query) AS t LEFT JOIN schema.posts AS j0 ON j0.id = t.current_post_id
+func (p *Postgres) joinedSelectQuery(s *query.Scope, q *selectQuery) (string, error) {
+	sb := &strings.Builder{}
+	sb.WriteString("SELECT ")
+	for i, field := range q.fieldsOrder {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		p.writeQualifiedColumn(sb, rootAlias, field)
+	}
+	for i, joined := range q.joined {
+		for _, field := range joined.fields {
+			sb.WriteString(", ")
+			p.writeQualifiedColumn(sb, joinAlias(i), field)
+		}
+	}
+	sb.WriteString(" FROM (")
+	sb.WriteString(q.query)
+	sb.WriteString(") AS ")
+	sb.WriteString(rootAlias)
+
+	for i, joined := range q.joined {
+		alias := joinAlias(i)
+		relation := joined.included.StructField.Relationship()
+		related := relation.RelatedModelStruct()
+		switch relation.Kind() {
+		case mapping.RelBelongsTo:
+			// LEFT JOIN schema.related AS j0 ON j0.id = t.foreign_key
+			sb.WriteString(" LEFT JOIN ")
+			p.writeTableName(sb, related)
+			sb.WriteString(" AS ")
+			sb.WriteString(alias)
+			sb.WriteString(" ON ")
+			p.writeQualifiedColumn(sb, alias, related.Primary())
+			sb.WriteString(" = ")
+			p.writeQualifiedColumn(sb, rootAlias, relation.ForeignKey())
+			if deletedAt, hasDeletedAt := related.DeletedAt(); hasDeletedAt {
+				sb.WriteString(" AND ")
+				p.writeQualifiedColumn(sb, alias, deletedAt)
+				sb.WriteString(" IS NULL")
+			}
+		case mapping.RelHasOne:
+			// The lateral sub query assures that a single related model is joined to the root. The related models
+			// are ordered by their primary key so that the joined model is deterministic.
+			// LEFT JOIN LATERAL (SELECT id, body FROM schema.related WHERE foreign_key = t.id ORDER BY id LIMIT 1) AS j0 ON true
+			sb.WriteString(" LEFT JOIN LATERAL (SELECT ")
+			for j, field := range joined.fields {
+				if j != 0 {
+					sb.WriteString(", ")
+				}
+				p.writeQuotedWord(sb, field.DatabaseName)
+			}
+			sb.WriteString(" FROM ")
+			p.writeTableName(sb, related)
+			sb.WriteString(" WHERE ")
+			p.writeQuotedWord(sb, relation.ForeignKey().DatabaseName)
+			sb.WriteString(" = ")
+			p.writeQualifiedColumn(sb, rootAlias, s.ModelStruct.Primary())
+			if deletedAt, hasDeletedAt := related.DeletedAt(); hasDeletedAt {
+				sb.WriteString(" AND ")
+				p.writeQuotedWord(sb, deletedAt.DatabaseName)
+				sb.WriteString(" IS NULL")
+			}
+			sb.WriteString(" ORDER BY ")
+			p.writeQuotedWord(sb, related.Primary().DatabaseName)
+			sb.WriteString(" LIMIT 1) AS ")
+			sb.WriteString(alias)
+			sb.WriteString(" ON true")
+		default:
+			return "", errors.Wrapf(query.ErrInternal, "invalid joined relationship: '%s' kind: '%s'", joined.included.StructField, relation.Kind())
+		}
+	}
+
+	// The join doesn't preserve the sub query order - sort the results once again.
+	if err := p.parseSelectSort(s, q.sorts, sb, rootAlias); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// scanJoinedRow scans the row of the joined select query into the root model and its joined relations.
+func (p *Postgres) scanJoinedRow(s *query.Scope, q *selectQuery, rows pgx.Rows) error {
+	root, err := newModelScanner(s.ModelStruct, q.fieldsOrder)
+	if err != nil {
+		return err
+	}
+	values := root.values
+
+	// The related model is found only if its primary key is not null.
+	rawValues := rows.RawValues()
+	offset := len(q.fieldsOrder)
+	related := make([]*modelScanner, len(q.joined))
+	for i, joined := range q.joined {
+		if rawValues[offset] == nil {
+			for range joined.fields {
+				values = append(values, discardScanner{})
+			}
+		} else {
+			related[i], err = newModelScanner(joined.included.StructField.Relationship().RelatedModelStruct(), joined.fields)
+			if err != nil {
+				return err
+			}
+			values = append(values, related[i].values...)
+		}
+		offset += len(joined.fields)
+	}
+
+	if err = rows.Scan(values...); err != nil {
+		return err
+	}
+	if err = root.setTimePointers(); err != nil {
+		return err
+	}
+
+	for i, scanner := range related {
+		if scanner == nil {
+			continue
+		}
+		if err = scanner.setTimePointers(); err != nil {
+			return err
+		}
+		relationer, ok := root.model.(mapping.SingleRelationer)
+		if !ok {
+			return errors.Wrapf(mapping.ErrModelNotImplements, "model: '%s' doesn't implement SingleRelationer interface", s.ModelStruct)
+		}
+		if err = relationer.SetRelationModel(q.joined[i].included.StructField, scanner.model); err != nil {
+			return err
+		}
+	}
+	s.Models = append(s.Models, root.model)
+	return nil
+}
+
+// findBatchedRelation finds the has many or many to many 'included' relation models of all scope's models
+// in a single query, and adds them to the matching root models.
+func (p *Postgres) findBatchedRelation(ctx context.Context, s *query.Scope, included *query.IncludedRelation) error {
+	relation := included.StructField.Relationship()
+	related := relation.RelatedModelStruct()
+
+	// Map the root models by their primary keys.
+	roots := map[interface{}]mapping.Model{}
+	var primaryKeys []interface{}
+	for _, model := range s.Models {
+		if model.IsPrimaryKeyZero() {
+			continue
+		}
+		primaryKey := model.GetPrimaryKeyHashableValue()
+		if _, ok := roots[primaryKey]; ok {
+			continue
+		}
+		roots[primaryKey] = model
+		primaryKeys = append(primaryKeys, model.GetPrimaryKeyValue())
+	}
+	if len(primaryKeys) == 0 {
+		return nil
+	}
+
+	fields := includedFields(included)
+	relatedScope := query.NewScope(related)
+	var args []interface{}
+	sb := &strings.Builder{}
+	sb.WriteString("SELECT ")
+	// rootKey is used to scan the root primary key, matched by the last selected column. The foreign key might be of
+	// a different type than the root primary key, thus it is scanned into the root primary key for the hashable values
+	// to match.
+	rootKey := mapping.NewModel(s.ModelStruct)
+	switch relation.Kind() {
+	case mapping.RelHasMany:
+		// SELECT id, title, blog_id, blog_id FROM schema.posts WHERE blog_id IN ($1,$2)
+		if !containsField(fields, relation.ForeignKey()) {
+			fields = append(fields, relation.ForeignKey())
+		}
+		for _, field := range fields {
+			p.writeQuotedWord(sb, field.DatabaseName)
+			sb.WriteString(", ")
+		}
+		p.writeQuotedWord(sb, relation.ForeignKey().DatabaseName)
+		sb.WriteString(" FROM ")
+		p.writeTableName(sb, related)
+		sb.WriteString(" WHERE ")
+		p.writeQuotedWord(sb, relation.ForeignKey().DatabaseName)
+		args = writeInValues(relatedScope, sb, primaryKeys)
+		if deletedAt, hasDeletedAt := related.DeletedAt(); hasDeletedAt {
+			sb.WriteString(" AND ")
+			p.writeQuotedWord(sb, deletedAt.DatabaseName)
+			sb.WriteString(" IS NULL")
+		}
+	case mapping.RelMany2Many:
+		// SELECT r.id, r.name, j.foreign_key FROM schema.related AS r JOIN schema.join AS j ON j.mtm_foreign_key = r.id WHERE j.foreign_key IN ($1,$2)
+		const relatedAlias, joinAlias = "r", "j"
+		for _, field := range fields {
+			p.writeQualifiedColumn(sb, relatedAlias, field)
+			sb.WriteString(", ")
+		}
+		p.writeQualifiedColumn(sb, joinAlias, relation.ForeignKey())
+		sb.WriteString(" FROM ")
+		p.writeTableName(sb, related)
+		sb.WriteString(" AS " + relatedAlias + " JOIN ")
+		p.writeTableName(sb, relation.JoinModel())
+		sb.WriteString(" AS " + joinAlias + " ON ")
+		p.writeQualifiedColumn(sb, joinAlias, relation.ManyToManyForeignKey())
+		sb.WriteString(" = ")
+		p.writeQualifiedColumn(sb, relatedAlias, related.Primary())
+		sb.WriteString(" WHERE ")
+		p.writeQualifiedColumn(sb, joinAlias, relation.ForeignKey())
+		args = writeInValues(relatedScope, sb, primaryKeys)
+		if deletedAt, hasDeletedAt := related.DeletedAt(); hasDeletedAt {
+			sb.WriteString(" AND ")
+			p.writeQualifiedColumn(sb, relatedAlias, deletedAt)
+			sb.WriteString(" IS NULL")
+		}
+	default:
+		return errors.Wrapf(query.ErrInternal, "invalid batched relationship: '%s' kind: '%s'", included.StructField, relation.Kind())
+	}
+
+	if log.Level() == log.LevelDebug3 {
+		log.Debug3f("[SCOPE][%s] batched included relation: '%s' query: %s", s.ID, included.StructField, sb.String())
+	}
+	rows, err := p.connection(s).Query(ctx, sb.String(), args...)
+	if err != nil {
+		return p.neuronError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		scanner, err := newModelScanner(related, fields)
+		if err != nil {
+			return err
+		}
+		values := append(scanner.values, rootKey.GetPrimaryKeyAddress())
+		if err = rows.Scan(values...); err != nil {
+			return errors.Wrapf(p.neuronError(err), "scanning row failed: %v", err)
+		}
+		if err = scanner.setTimePointers(); err != nil {
+			return err
+		}
+
+		rootPrimaryKey := rootKey.GetPrimaryKeyHashableValue()
+		root, ok := roots[rootPrimaryKey]
+		if !ok {
+			log.Debugf("[SCOPE][%s] no root model found for the included relation: '%s' with the key: '%v'", s.ID, included.StructField, rootPrimaryKey)
+			continue
+		}
+		relationer, ok := root.(mapping.MultiRelationer)
+		if !ok {
+			return errors.Wrapf(mapping.ErrModelNotImplements, "model: '%s' doesn't implement MultiRelationer interface", s.ModelStruct)
+		}
+		if err = relationer.AddRelationModel(included.StructField, scanner.model); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return p.neuronError(err)
+	}
+	return nil
+}
+
+// includedFields gets the non skipped database fields for the included relation. The related model primary key
+// is always the first field.
+func includedFields(included *query.IncludedRelation) []*mapping.StructField {
+	related := included.StructField.Relationship().RelatedModelStruct()
+	fieldSet := included.Fieldset
+	if len(fieldSet) == 0 {
+		fieldSet = related.Fields()
+	}
+	fields := []*mapping.StructField{related.Primary()}
+	for _, field := range fieldSet {
+		if field.DatabaseSkip() || field == related.Primary() {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func containsField(fields []*mapping.StructField, field *mapping.StructField) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func joinAlias(i int) string {
+	return "j" + strconv.Itoa(i)
+}
+
+// writeInValues writes the IN clause for the 'values' and returns the query arguments. Large value lists are
+// written as a single array parameter - '= ANY($1)', so that the query doesn't exceed the parameters limit.
+func writeInValues(s *query.Scope, sb *strings.Builder, values []interface{}) []interface{} {
+	if len(values) > internal.InArrayThreshold {
+		if array, ok := internal.ArrayValue(values); ok {
+			sb.WriteString(" = ANY(")
+			sb.WriteString(internal.StringIncrementor(s))
+			sb.WriteRune(')')
+			return []interface{}{array}
+		}
+	}
+	sb.WriteString(" IN (")
+	for i := range values {
+		if i != 0 {
+			sb.WriteRune(',')
+		}
+		sb.WriteString(internal.StringIncrementor(s))
+	}
+	sb.WriteRune(')')
+	return values
+}
+
+func (p *Postgres) writeTableName(sb *strings.Builder, mStruct *mapping.ModelStruct) {
+	p.writeQuotedWord(sb, mStruct.DatabaseSchemaName)
+	sb.WriteRune('.')
+	p.writeQuotedWord(sb, mStruct.DatabaseName)
+}
+
+func (p *Postgres) writeQualifiedColumn(sb *strings.Builder, alias string, field *mapping.StructField) {
+	sb.WriteString(alias)
+	sb.WriteRune('.')
+	p.writeQuotedWord(sb, field.DatabaseName)
+}
+
+// discardScanner is the scan destination that discards the scanned value.
+type discardScanner struct{}
+
+// DecodeBinary implements pgtype.BinaryDecoder interface.
+func (discardScanner) DecodeBinary(*pgtype.ConnInfo, []byte) error {
+	return nil
+}
+
+// DecodeText implements pgtype.TextDecoder interface.
+func (discardScanner) DecodeText(*pgtype.ConnInfo, []byte) error {
+	return nil
+}
@@ -12,8 +12,6 @@ import (
 	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
-
-	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
 )
 
 // Insert depending on the query efficiently inserts models with related fieldSets.
@@ -30,18 +28,51 @@ func (p *Postgres) Insert(ctx context.Context, s *query.Scope) error {
 //
 
 func (p *Postgres) insertWithCommonFieldSet(ctx context.Context, s *query.Scope) error {
-	q, err := p.parseInsertWithCommonFieldSet(s)
+	if p.isCopyInsert(s) {
+		return p.insertWithCopy(ctx, s)
+	}
+	queries, err := p.parseInsertWithCommonFieldSet(s)
 	if err != nil {
 		log.Debug2f("parsing insert query failed: %v", err)
 		return err
 	}
+	if len(queries) == 1 || s.Transaction != nil {
+		conn := p.connection(s)
+		for _, q := range queries {
+			if err = p.execInsertQuery(ctx, conn, q); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
+	// The models split into multiple queries are inserted within a single transaction, so that none of them
+	// is inserted if any of the queries fails.
+	tx, err := p.ConnPool.Begin(ctx)
+	if err != nil {
+		return errors.WrapDetf(p.neuronError(err), "begin insert transaction failed: %v", err)
+	}
+	for _, q := range queries {
+		if err = p.execInsertQuery(ctx, tx, q); err != nil {
+			if er := tx.Rollback(ctx); er != nil {
+				log.Errorf("Rolling back insert transaction failed: %v", er)
+			}
+			return err
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return errors.WrapDetf(p.neuronError(err), "commit insert transaction failed: %v", err)
+	}
+	return nil
+}
+
+func (p *Postgres) execInsertQuery(ctx context.Context, conn internal.Connection, q *insertQuery) error {
 	if log.Level().IsAllowed(log.LevelDebug3) {
 		log.Debug3f("%s", q.query)
 	}
 
-	if q.primarySelected {
-		_, err := p.connection(s).Exec(ctx, q.query, q.values...)
+	if len(q.returning) == 0 {
+		_, err := conn.Exec(ctx, q.query, q.values...)
 		if err != nil {
 			log.Debugf("insert query failed: %v", err)
 			return errors.WrapDetf(p.neuronError(err), "inserting failed: %v", err)
@@ -49,23 +80,55 @@ func (p *Postgres) insertWithCommonFieldSet(ctx context.Context, s *query.Scope)
 		return nil
 	}
 
-	rows, err := p.connection(s).Query(ctx, q.query, q.values...)
+	rows, err := conn.Query(ctx, q.query, q.values...)
 	if err != nil {
 		log.Debugf("Insert query failed: %v", err)
 		return errors.WrapDetf(p.neuronError(err), "insert query failed")
 	}
+	defer rows.Close()
+
 	var i int
 	for rows.Next() {
-		if err = rows.Scan(s.Models[i].GetPrimaryKeyAddress()); err != nil {
+		if err = scanReturning(rows, q.models[i], q.returning); err != nil {
 			log.Debugf("Scanning failed: %v", err)
 			return errors.WrapDetf(p.neuronError(err), "inserting failed: %v", err)
 		}
 		i++
 	}
-	rows.Close()
+	if err = rows.Err(); err != nil {
+		return errors.WrapDetf(p.neuronError(err), "inserting failed: %v", err)
+	}
 	return nil
 }
 
+// scanReturningRow scans the 'returning' fields values of the single 'row' into given 'model'.
+// Returns false if no row was found.
+func scanReturningRow(row pgx.Row, model mapping.Model, returning []*mapping.StructField) (bool, error) {
+	scanner, err := newModelFieldsScanner(model, returning)
+	if err != nil {
+		return false, err
+	}
+	if err = row.Scan(scanner.values...); err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, scanner.setTimePointers()
+}
+
+// scanReturning scans the 'returning' fields values of the current row into given 'model'.
+func scanReturning(rows pgx.Rows, model mapping.Model, returning []*mapping.StructField) error {
+	scanner, err := newModelFieldsScanner(model, returning)
+	if err != nil {
+		return err
+	}
+	if err = rows.Scan(scanner.values...); err != nil {
+		return err
+	}
+	return scanner.setTimePointers()
+}
+
 func (p *Postgres) insertWithBulkFieldSet(ctx context.Context, s *query.Scope) error {
 	b := &pgx.Batch{}
 	q, err := p.parseInsertBulkFieldsetQuery(s, b)
@@ -76,8 +139,8 @@ func (p *Postgres) insertWithBulkFieldSet(ctx context.Context, s *query.Scope) e
 	br := p.connection(s).SendBatch(ctx, b)
 	defer br.Close()
 
-	for _, indices := range q {
-		switch len(indices) {
+	for _, bq := range q {
+		switch len(bq.returning) {
 		case 0:
 			if _, err = br.Exec(); err != nil {
 				return errors.WrapDetf(p.neuronError(err), "insert failed: %v", err)
@@ -90,130 +153,120 @@ func (p *Postgres) insertWithBulkFieldSet(ctx context.Context, s *query.Scope) e
 
 			var i int
 			for rows.Next() {
-				if err = rows.Scan(s.Models[indices[i]].GetPrimaryKeyAddress()); err != nil {
+				if err = scanReturning(rows, s.Models[bq.indices[i]], bq.returning); err != nil {
 					rows.Close()
 					return errors.WrapDetf(p.neuronError(err), "insert failed: %v", err)
 				}
 				i++
 			}
 			rows.Close()
+			if err = rows.Err(); err != nil {
+				return errors.WrapDetf(p.neuronError(err), "insert failed: %v", err)
+			}
 		}
 	}
 	return nil
 }
 
 type insertQuery struct {
-	query           string
-	values          []interface{}
-	primarySelected bool
+	query     string
+	values    []interface{}
+	returning []*mapping.StructField
+	// models are the models inserted by the query.
+	models []mapping.Model
 }
 
-func (p *Postgres) parseInsertWithCommonFieldSet(s *query.Scope) (*insertQuery, error) {
+// bulkInsertQuery is the batched insert query for the models with the same fieldset.
+type bulkInsertQuery struct {
+	// indices are the scope's model indices inserted by the query.
+	indices []int
+	// returning are the fields scanned back into the models.
+	returning []*mapping.StructField
+}
+
+// parseInsertWithCommonFieldSet prepares the insert queries for the scope's models with the common fieldset.
+// If the models values exceeds the statement parameters limit, the models are split into multiple queries.
+func (p *Postgres) parseInsertWithCommonFieldSet(s *query.Scope) ([]*insertQuery, error) {
 	// Models length is already checked - must be one.
 	commonFieldSet, hasCommonFieldSet := s.CommonFieldSet()
 	if !hasCommonFieldSet {
 		return nil, errors.WrapDetf(query.ErrInvalidFieldSet, "no insert fieldset provided")
 	}
 	mStruct := s.ModelStruct
-	fieldSet, autoSelected := p.prepareInsertFieldset(mStruct, commonFieldSet)
+	insertDefaults := p.isInsertDefaults(s)
+	fieldSet, autoSelected := p.prepareInsertFieldset(mStruct, commonFieldSet, insertDefaults)
+	fields := &insertFields{fieldSet: fieldSet, autoSelected: autoSelected, clockValue: p.clockValue(), defaults: insertDefaults}
 
-	iq := &insertQuery{}
+	var primarySelected bool
 	sb := &strings.Builder{}
 	// Build the query of form "INSERT INTO schemaName.tableName (fields) VALUES (fieldValues)"
 	sb.WriteString("INSERT INTO ")
 	p.writeQuotedWord(sb, mStruct.DatabaseSchemaName)
 	sb.WriteRune('.')
 	p.writeQuotedWord(sb, mStruct.DatabaseName)
-
 	if len(fieldSet) > 0 {
 		sb.WriteString(" (")
 		for i, field := range fieldSet {
 			if field.Kind() == mapping.KindPrimary {
-				iq.primarySelected = true
+				primarySelected = true
 			}
 			p.writeQuotedWord(sb, field.DatabaseName)
 			if i != len(fieldSet)-1 {
 				sb.WriteRune(',')
 			}
 		}
-		sb.WriteString(") VALUES ")
-		for j, model := range s.Models {
-			sb.WriteRune('(')
-			// Get the model and get selected field values.
-			fielder, isFielder := model.(mapping.Fielder)
-			if !isFielder && (len(fieldSet) > 1 || ((len(fieldSet) == 1) && fieldSet[0].Kind() != mapping.KindPrimary)) {
-				return nil, errors.Wrapf(mapping.ErrModelNotImplements, "Model: '%s' doesn't implement Fielder interface", mStruct)
-			}
-
-			// Add the selected fields to the query.
-			var (
-				fieldValue interface{}
-				err        error
-			)
-			for i, field := range fieldSet {
-				switch field.Kind() {
-				case mapping.KindPrimary:
-					iq.values = append(iq.values, model.GetPrimaryKeyValue())
-				default:
-					if autoSelected != nil && autoSelected.Contains(field) {
-						fieldValue, err = fielder.GetFieldZeroValue(field)
-					} else {
-						fieldValue, err = fielder.GetFieldValue(field)
-					}
-					if err != nil {
-						return nil, err
-					}
-					iq.values = append(iq.values, fieldValue)
-				}
-
-				// Write value string incrementor.
-				sb.WriteRune('$')
-				sb.WriteString(strconv.Itoa(internal.Incrementor(s)))
+		sb.WriteRune(')')
+	}
+	sb.WriteString(" VALUES ")
+	header := sb.String()
+	returning := insertReturning(mStruct, fieldSet, primarySelected, insertDefaults)
 
-				// Add comma if there is more fields to write
-				if i != len(fieldSet)-1 {
-					sb.WriteRune(',')
+	var queries []*insertQuery
+	chunkSize := internal.ChunkSize(len(fieldSet), 0)
+	for start := 0; start < len(s.Models); start += chunkSize {
+		end := start + chunkSize
+		if end > len(s.Models) {
+			end = len(s.Models)
+		}
+		iq := &insertQuery{returning: returning, models: s.Models[start:end]}
+		sb.Reset()
+		sb.WriteString(header)
+		for j, model := range iq.models {
+			if len(fieldSet) > 0 {
+				sb.WriteRune('(')
+				var err error
+				if iq.values, err = p.writeInsertValues(s, sb, fields, model, iq.values); err != nil {
+					return nil, err
 				}
+				sb.WriteRune(')')
+			} else {
+				sb.WriteString("(DEFAULT)")
 			}
-			sb.WriteRune(')')
-			if j != len(s.Models)-1 {
+			if j != len(iq.models)-1 {
 				sb.WriteRune(',')
 			}
 		}
-	} else {
-		sb.WriteString(" VALUES ")
-		for i := range s.Models {
-			sb.WriteString("(DEFAULT)")
-			if i != len(s.Models)-1 {
-				sb.WriteRune(',')
-			}
-		}
-	}
-	if !iq.primarySelected {
-		sb.WriteString(" RETURNING ")
-		p.writeQuotedWord(sb, mStruct.Primary().DatabaseName)
+		p.writeReturning(sb, returning)
+		iq.query = sb.String()
+		queries = append(queries, iq)
+		internal.ResetIncrementor(s)
 	}
-	iq.query = sb.String()
-	return iq, nil
+	return queries, nil
 }
 
 // parseInsertBulkFieldSetQuery prepares the string query with the bulk fieldset for provided models.
-func (p *Postgres) parseInsertBulkFieldsetQuery(s *query.Scope, batch internal.Batch) (queryIndices [][]int, err error) {
+func (p *Postgres) parseInsertBulkFieldsetQuery(s *query.Scope, batch internal.Batch) (queries []*bulkInsertQuery, err error) {
 	mStruct := s.ModelStruct
-	primaryKeyName := migrate.GetQuotedWord(mStruct.Primary().DatabaseName, p.postgresVersion)
-	var (
-		sb           strings.Builder
-		autoSelected mapping.FieldSet
-	)
+	clockValue := p.clockValue()
+	insertDefaults := p.isInsertDefaults(s)
+	var sb strings.Builder
 
 	bulk := &mapping.BulkFieldSet{}
 	for i, fieldSet := range s.FieldSets {
 		bulk.Add(fieldSet, i)
 	}
 
-	queryIndices = make([][]int, len(bulk.FieldSets))
 	for i := range bulk.FieldSets {
-		var values []interface{}
 		sb.WriteString("INSERT INTO ")
 		p.writeQuotedWord(&sb, mStruct.DatabaseSchemaName)
 		sb.WriteRune('.')
@@ -222,7 +275,9 @@ func (p *Postgres) parseInsertBulkFieldsetQuery(s *query.Scope, batch internal.B
 		// Get the fieldset and related model indices, add to the query indices and trim the fieldset.
 		fieldSet := bulk.FieldSets[i]
 		indices := bulk.GetIndicesByFieldset(fieldSet)
-		fieldSet, autoSelected = p.prepareInsertFieldset(s.ModelStruct, fieldSet)
+		fields := &insertFields{clockValue: clockValue, defaults: insertDefaults}
+		fields.fieldSet, fields.autoSelected = p.prepareInsertFieldset(s.ModelStruct, fieldSet, insertDefaults)
+		fieldSet = fields.fieldSet
 
 		var primarySelected bool
 		// Write fieldset column names (id, name, surname).
@@ -241,56 +296,137 @@ func (p *Postgres) parseInsertBulkFieldsetQuery(s *query.Scope, batch internal.B
 		}
 		sb.WriteString(" VALUES ")
 
-		// Write comma separated, wrapped in brackets field value for given models i.e. ($1,$2,$3),($4,$5,$6).
-		for j, index := range indices {
-			sb.WriteRune('(')
-			if len(fieldSet) != 0 {
-				model := s.Models[index]
-				fielder, isFielder := model.(mapping.Fielder)
-				if !isFielder && (len(fieldSet) > 1 || ((len(fieldSet) == 1) && fieldSet[0].Kind() != mapping.KindPrimary)) {
-					return nil, errors.Wrapf(mapping.ErrModelNotImplements, "Model: '%s' doesn't implement Fielder interface", s.ModelStruct)
-				}
-
-				var fieldValue interface{}
-				for k, field := range fieldSet {
-					switch field.Kind() {
-					case mapping.KindPrimary:
-						values = append(values, model.GetPrimaryKeyValue())
-					default:
-						if autoSelected != nil && autoSelected.Contains(field) {
-							fieldValue, err = fielder.GetFieldZeroValue(field)
-						} else {
-							fieldValue, err = fielder.GetFieldValue(field)
-						}
-						if err != nil {
-							return nil, err
-						}
-						values = append(values, fieldValue)
-					}
+		header := sb.String()
+		sb.Reset()
+		returning := insertReturning(mStruct, fieldSet, primarySelected, insertDefaults)
 
-					sb.WriteRune('$')
-					sb.WriteString(strconv.Itoa(internal.Incrementor(s)))
-					if k != len(fieldSet)-1 {
-						sb.WriteRune(',')
+		// The models that exceeds the statement parameters limit are inserted with the next queries.
+		chunkSize := internal.ChunkSize(len(fieldSet), 0)
+		for start := 0; start < len(indices); start += chunkSize {
+			end := start + chunkSize
+			if end > len(indices) {
+				end = len(indices)
+			}
+			var values []interface{}
+			sb.WriteString(header)
+			// Write comma separated, wrapped in brackets field value for given models i.e. ($1,$2,$3),($4,$5,$6).
+			for j, index := range indices[start:end] {
+				sb.WriteRune('(')
+				if len(fieldSet) != 0 {
+					if values, err = p.writeInsertValues(s, &sb, fields, s.Models[index], values); err != nil {
+						return nil, err
 					}
+				} else {
+					sb.WriteString("DEFAULT")
 				}
-			} else {
+				sb.WriteRune(')')
+				if j != end-start-1 {
+					sb.WriteRune(',')
+				}
+			}
+
+			queries = append(queries, &bulkInsertQuery{indices: indices[start:end], returning: returning})
+			p.writeReturning(&sb, returning)
+			batch.Queue(sb.String(), values...)
+			sb.Reset()
+			internal.ResetIncrementor(s)
+		}
+	}
+	return queries, nil
+}
+
+// insertFields are the fields inserted with the same query.
+type insertFields struct {
+	fieldSet mapping.FieldSet
+	// autoSelected are the fields that were not selected by the user, but added to the fieldset by the repository.
+	autoSelected mapping.FieldSet
+	// clockValue is the value of the automatically set timestamps. If nil the now() function is used.
+	clockValue interface{}
+	// defaults defines if the zero primary keys should be set to their column defaults.
+	defaults bool
+}
+
+// writeInsertValues writes comma separated 'model' values of the insert 'fields' and returns the query arguments
+// extended by the model's values.
+func (p *Postgres) writeInsertValues(s *query.Scope, sb *strings.Builder, fields *insertFields, model mapping.Model, values []interface{}) ([]interface{}, error) {
+	fieldSet := fields.fieldSet
+	// Get the model and get selected field values.
+	fielder, isFielder := model.(mapping.Fielder)
+	if !isFielder && (len(fieldSet) > 1 || ((len(fieldSet) == 1) && fieldSet[0].Kind() != mapping.KindPrimary)) {
+		return nil, errors.Wrapf(mapping.ErrModelNotImplements, "Model: '%s' doesn't implement Fielder interface", s.ModelStruct)
+	}
+
+	var (
+		fieldValue interface{}
+		err        error
+	)
+	for i, field := range fieldSet {
+		if i != 0 {
+			sb.WriteRune(',')
+		}
+		if isTimestampField(field) {
+			autoTimestamp, err := isAutoTimestamp(fielder, field, fields.autoSelected)
+			if err != nil {
+				return nil, err
+			}
+			if autoTimestamp {
+				values = p.writeTimestamp(s, sb, values, fields.clockValue)
+				continue
+			}
+		}
+		switch field.Kind() {
+		case mapping.KindPrimary:
+			if fields.defaults && model.IsPrimaryKeyZero() {
 				sb.WriteString("DEFAULT")
+				continue
 			}
-			sb.WriteRune(')')
-			if j != len(indices)-1 {
-				sb.WriteRune(',')
+			values = append(values, model.GetPrimaryKeyValue())
+		default:
+			// The selected fields are always inserted with their values - the column defaults are used only for
+			// the fields not selected for the insert.
+			if fields.autoSelected != nil && fields.autoSelected.Contains(field) {
+				fieldValue, err = fielder.GetFieldZeroValue(field)
+			} else {
+				fieldValue, err = fielder.GetFieldValue(field)
+			}
+			if err != nil {
+				return nil, err
 			}
+			values = append(values, fieldValue)
 		}
 
-		if !primarySelected {
-			sb.WriteString(" RETURNING ")
-			sb.WriteString(primaryKeyName)
-			queryIndices[i] = indices
+		// Write value string incrementor.
+		sb.WriteRune('$')
+		sb.WriteString(strconv.Itoa(internal.Incrementor(s)))
+	}
+	return values, nil
+}
+
+// isInsertDefaults checks if the insert query should use the column defaults for the zero primary keys
+// and the fields not selected for the insert.
+func (p *Postgres) isInsertDefaults(s *query.Scope) bool {
+	return p.InsertDefaults || isOptionSet(s, internal.InsertDefaultsKey)
+}
+
+// insertReturning gets the fields returned by the insert query - the primary key if it was not selected
+// and the automatically set timestamps. If the column 'defaults' are used, all the model's columns are returned,
+// as any of them might be set by the database.
+func insertReturning(mStruct *mapping.ModelStruct, fieldSet mapping.FieldSet, primarySelected, defaults bool) (returning []*mapping.StructField) {
+	if defaults {
+		for _, field := range mStruct.Fields() {
+			if !field.DatabaseSkip() {
+				returning = append(returning, field)
+			}
+		}
+		return returning
+	}
+	if !primarySelected {
+		returning = append(returning, mStruct.Primary())
+	}
+	for _, field := range fieldSet {
+		if isTimestampField(field) {
+			returning = append(returning, field)
 		}
-		batch.Queue(sb.String(), values...)
-		sb.Reset()
-		internal.ResetIncrementor(s)
 	}
-	return queryIndices, nil
+	return returning
 }
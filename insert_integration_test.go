@@ -13,6 +13,7 @@ import (
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
 	"github.com/neuronlabs/neuron/database"
 	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 )
 
@@ -81,3 +82,114 @@ func TestInsertSingleModel(t *testing.T) {
 		}
 	})
 }
+
+func TestInsertCopy(t *testing.T) {
+	c := testingController(t, true, testModels...)
+	p := testingRepository(c)
+
+	ctx := context.Background()
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	defer func() {
+		_ = internal.DropTables(ctx, p.ConnPool, mStruct.DatabaseName, mStruct.DatabaseSchemaName)
+	}()
+
+	p.CopyThreshold = 2
+	defer func() {
+		p.CopyThreshold = DefaultCopyThreshold
+	}()
+
+	models := []mapping.Model{&tests.Model{AttrString: "first"}, &tests.Model{AttrString: "second"}, &tests.Model{AttrString: "third"}}
+	db := database.New(c)
+	err = db.Query(mStruct, models...).Select(mStruct.MustFieldByName("AttrString")).Insert()
+	require.NoError(t, err)
+
+	ids := map[int]struct{}{}
+	for _, model := range models {
+		m := model.(*tests.Model)
+		assert.NotZero(t, m.ID)
+		assert.False(t, m.CreatedAt.IsZero())
+		ids[m.ID] = struct{}{}
+	}
+	assert.Len(t, ids, 3)
+
+	count, err := db.Query(mStruct).Count()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestInsertChunks(t *testing.T) {
+	c := testingController(t, true, testModels...)
+	p := testingRepository(c)
+
+	ctx := context.Background()
+	mStruct, err := c.ModelStruct(&tests.SimpleModel{})
+	require.NoError(t, err)
+
+	defer func() {
+		_ = internal.DropTables(ctx, p.ConnPool, mStruct.DatabaseName, mStruct.DatabaseSchemaName)
+	}()
+
+	p.CopyThreshold = 0
+	defer func() {
+		p.CopyThreshold = DefaultCopyThreshold
+	}()
+
+	// The models exceeds the statement parameters limit, thus these are split into multiple queries.
+	// The last model duplicates the primary key of the first one, so that the last query fails.
+	models := make([]mapping.Model, internal.MaxParameters)
+	for i := range models {
+		models[i] = &tests.SimpleModel{ID: i + 1, Attr: "chunk"}
+	}
+	models[len(models)-1].(*tests.SimpleModel).ID = 1
+
+	db := database.New(c)
+	err = db.Query(mStruct, models...).Select(mStruct.Primary(), mStruct.MustFieldByName("Attr")).Insert()
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, query.ErrViolationUnique))
+	}
+
+	count, err := db.Query(mStruct).Count()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+// TestInsertDefaults tests the insert of the models with the column defaults.
+func TestInsertDefaults(t *testing.T) {
+	c := testingController(t, true, testModels...)
+	p := testingRepository(c)
+
+	ctx := context.Background()
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	defer func() {
+		_ = internal.DropTables(ctx, p.ConnPool, mStruct.DatabaseName, mStruct.DatabaseSchemaName)
+	}()
+
+	_, err = p.ConnPool.Exec(ctx, "ALTER TABLE public.models ALTER COLUMN int SET DEFAULT 42")
+	require.NoError(t, err)
+
+	p.InsertDefaults = true
+	defer func() {
+		p.InsertDefaults = false
+	}()
+
+	db := database.New(c)
+	// The field not selected for the insert is set by its column default and scanned back into the model.
+	omitted := &tests.Model{AttrString: "omitted"}
+	require.NoError(t, db.Query(mStruct, omitted).Select(mStruct.MustFieldByName("AttrString")).Insert())
+	assert.NotZero(t, omitted.ID)
+	assert.Equal(t, 42, omitted.Int)
+
+	// The selected zero value field is inserted with its value.
+	selected := &tests.Model{AttrString: "selected"}
+	require.NoError(t, db.Query(mStruct, selected).Select(mStruct.MustFieldByName("AttrString"), mStruct.MustFieldByName("Int")).Insert())
+	assert.Equal(t, 0, selected.Int)
+
+	// Without the fieldset the neuron selects only the non zero fields.
+	unselected := &tests.Model{AttrString: "unselected"}
+	require.NoError(t, db.Query(mStruct, unselected).Insert())
+	assert.Equal(t, 42, unselected.Int)
+}
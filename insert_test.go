@@ -30,17 +30,19 @@ func TestParseInsertQuery(t *testing.T) {
 	s := query.NewScope(m, model)
 	// get rid of the primary field.
 	s.FieldSets = []mapping.FieldSet{m.Fields()[1:]}
-	q, err := repo.parseInsertWithCommonFieldSet(s)
+	queries, err := repo.parseInsertWithCommonFieldSet(s)
 	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	q := queries[0]
 
-	assert.Equal(t, "INSERT INTO public.models (attr_string,string_ptr,int,created_at,updated_at,deleted_at) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id", q.query)
-	if assert.Len(t, q.values, 6) {
+	// The UpdatedAt field is not set - it is set automatically by the server.
+	assert.Equal(t, "INSERT INTO public.models (attr_string,string_ptr,int,created_at,updated_at,deleted_at) VALUES ($1,$2,$3,$4,now(),$5) RETURNING id, created_at, updated_at", q.query)
+	if assert.Len(t, q.values, 5) {
 		assert.Equal(t, model.AttrString, q.values[0])
 		assert.Equal(t, model.StringPtr, q.values[1])
 		assert.Equal(t, model.Int, q.values[2])
 		assert.Equal(t, model.CreatedAt, q.values[3])
-		assert.Equal(t, model.UpdatedAt, q.values[4])
-		assert.Equal(t, model.DeletedAt, q.values[5])
+		assert.Equal(t, model.DeletedAt, q.values[4])
 	}
 }
 
@@ -61,10 +63,12 @@ func TestParseWithDefault(t *testing.T) {
 	s := query.NewScope(m, model)
 	s.FieldSets = append(s.FieldSets, mapping.FieldSet{})
 
-	q, err := p.parseInsertWithCommonFieldSet(s)
+	queries, err := p.parseInsertWithCommonFieldSet(s)
 	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	q := queries[0]
 
-	assert.Equal(t, "INSERT INTO public.models VALUES (DEFAULT) RETURNING id", q.query)
+	assert.Equal(t, "INSERT INTO public.models (created_at,updated_at) VALUES (now(),now()) RETURNING id, created_at, updated_at", q.query)
 	assert.Len(t, q.values, 0)
 }
 
@@ -96,28 +100,28 @@ func TestParseBulkInsert(t *testing.T) {
 	secondFieldset := mapping.FieldSet{m.MustFieldByName("AttrString"), m.MustFieldByName("CreatedAt")}
 	s := query.NewScope(m, model, model2, model3)
 	s.FieldSets = []mapping.FieldSet{firstFieldset, secondFieldset, secondFieldset}
-	queryIndices, err := repo.parseInsertBulkFieldsetQuery(s, batch)
+	queries, err := repo.parseInsertBulkFieldsetQuery(s, batch)
 	require.NoError(t, err)
 
-	if assert.Len(t, queryIndices, 2) {
-		assert.Len(t, queryIndices[0], 0)
-		if assert.Len(t, queryIndices[1], 2) {
-			assert.Equal(t, queryIndices[1][0], 1)
-			assert.Equal(t, queryIndices[1][1], 2)
+	if assert.Len(t, queries, 2) {
+		assert.Equal(t, []int{0}, queries[0].indices)
+		if assert.Len(t, queries[1].indices, 2) {
+			assert.Equal(t, queries[1].indices[0], 1)
+			assert.Equal(t, queries[1].indices[1], 2)
 		}
 	}
 
 	firstQuery := batch.Queries[0]
-	assert.Equal(t, "INSERT INTO public.models (id,attr_string,int,created_at) VALUES ($1,$2,$3,$4)", firstQuery.Query)
-	if assert.Len(t, firstQuery.Arguments, 4) {
+	// Zero value CreatedAt and not selected UpdatedAt are set automatically.
+	assert.Equal(t, "INSERT INTO public.models (id,attr_string,int,created_at,updated_at) VALUES ($1,$2,$3,now(),now()) RETURNING created_at, updated_at", firstQuery.Query)
+	if assert.Len(t, firstQuery.Arguments, 3) {
 		assert.Equal(t, model.ID, firstQuery.Arguments[0])
 		assert.Equal(t, model.AttrString, firstQuery.Arguments[1])
 		assert.Equal(t, model.Int, firstQuery.Arguments[2])
-		assert.Equal(t, time.Time{}, firstQuery.Arguments[3])
 	}
 
 	secondQuery := batch.Queries[1]
-	assert.Equal(t, "INSERT INTO public.models (attr_string,int,created_at) VALUES ($1,$2,$3),($4,$5,$6) RETURNING id", secondQuery.Query)
+	assert.Equal(t, "INSERT INTO public.models (attr_string,int,created_at,updated_at) VALUES ($1,$2,$3,now()),($4,$5,$6,now()) RETURNING id, created_at, updated_at", secondQuery.Query)
 	if assert.Len(t, secondQuery.Arguments, 6) {
 		assert.Equal(t, model2.AttrString, secondQuery.Arguments[0])
 		// This field was auto selected - it must be a zero value.
@@ -129,3 +133,102 @@ func TestParseBulkInsert(t *testing.T) {
 		assert.Equal(t, model3.CreatedAt, secondQuery.Arguments[5])
 	}
 }
+
+func TestParseInsertClock(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	m, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	now := time.Date(2020, 7, 17, 12, 0, 0, 0, time.UTC)
+	p.Clock = func() time.Time { return now }
+
+	model := &tests.Model{AttrString: "some"}
+	s := query.NewScope(m, model)
+	s.FieldSets = []mapping.FieldSet{{m.MustFieldByName("AttrString")}}
+
+	queries, err := p.parseInsertWithCommonFieldSet(s)
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	q := queries[0]
+
+	assert.Equal(t, "INSERT INTO public.models (attr_string,int,created_at,updated_at) VALUES ($1,$2,$3,$4) RETURNING id, created_at, updated_at", q.query)
+	assert.Equal(t, []interface{}{"some", 0, now, now}, q.values)
+}
+
+func TestParseInsertDefaults(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	m, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	t.Run("Common", func(t *testing.T) {
+		first := &tests.Model{AttrString: "first"}
+		second := &tests.Model{ID: 5, Int: 3}
+		s := query.NewScope(m, first, second)
+		s.FieldSets = []mapping.FieldSet{{m.Primary(), m.MustFieldByName("AttrString"), m.MustFieldByName("Int")}}
+		InsertDefaults(s)
+
+		queries, err := p.parseInsertWithCommonFieldSet(s)
+		require.NoError(t, err)
+		require.Len(t, queries, 1)
+		q := queries[0]
+
+		assert.Equal(t, "INSERT INTO public.models (id,attr_string,int,created_at,updated_at) VALUES (DEFAULT,$1,$2,now(),now()),($3,$4,$5,now(),now()) RETURNING id, attr_string, string_ptr, int, created_at, updated_at, deleted_at", q.query)
+		// The selected zero value fields are inserted with their values, only the zero primary key is set to DEFAULT.
+		assert.Equal(t, []interface{}{"first", 0, 5, "", 3}, q.values)
+		assert.Len(t, q.returning, len(m.Fields()))
+	})
+
+	t.Run("Bulk", func(t *testing.T) {
+		p.InsertDefaults = true
+		defer func() {
+			p.InsertDefaults = false
+		}()
+
+		s := query.NewScope(m, &tests.Model{AttrString: "first"}, &tests.Model{Int: 2})
+		s.FieldSets = []mapping.FieldSet{{m.MustFieldByName("AttrString")}, {m.MustFieldByName("Int")}}
+
+		batch := &internal.DummyBatch{}
+		queries, err := p.parseInsertBulkFieldsetQuery(s, batch)
+		require.NoError(t, err)
+		require.Len(t, queries, 2)
+		require.Equal(t, 2, batch.Len())
+
+		// The not null fields are not selected when the defaults are used.
+		assert.Equal(t, "INSERT INTO public.models (attr_string,created_at,updated_at) VALUES ($1,now(),now()) RETURNING id, attr_string, string_ptr, int, created_at, updated_at, deleted_at", batch.Queries[0].Query)
+		assert.Equal(t, []interface{}{"first"}, batch.Queries[0].Arguments)
+		assert.Equal(t, "INSERT INTO public.models (int,created_at,updated_at) VALUES ($1,now(),now()) RETURNING id, attr_string, string_ptr, int, created_at, updated_at, deleted_at", batch.Queries[1].Query)
+		assert.Equal(t, []interface{}{2}, batch.Queries[1].Arguments)
+	})
+}
+
+func TestParseInsertChunks(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	m, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	// The fieldset contains attr_string, int, created_at and updated_at fields.
+	chunkSize := internal.ChunkSize(4, 0)
+	models := make([]mapping.Model, chunkSize+1)
+	for i := range models {
+		models[i] = &tests.Model{AttrString: "some"}
+	}
+	s := query.NewScope(m, models...)
+	s.FieldSets = []mapping.FieldSet{{m.MustFieldByName("AttrString")}}
+
+	queries, err := p.parseInsertWithCommonFieldSet(s)
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+
+	assert.Len(t, queries[0].models, chunkSize)
+	assert.Len(t, queries[0].values, chunkSize*2)
+	assert.LessOrEqual(t, len(queries[0].values), internal.MaxParameters)
+
+	assert.Len(t, queries[1].models, 1)
+	assert.Equal(t, "INSERT INTO public.models (attr_string,int,created_at,updated_at) VALUES ($1,$2,now(),now()) RETURNING id, created_at, updated_at", queries[1].query)
+}
@@ -13,6 +13,7 @@ type Connection interface {
 	Query(ctx context.Context, query string, values ...interface{}) (pgx.Rows, error)
 	QueryRow(ctx context.Context, query string, values ...interface{}) pgx.Row
 	SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
 }
 
 // Batch is the interface used for the batch queries.
@@ -5,7 +5,9 @@ import (
 	"fmt"
 )
 
+// DropTables drops the table with given name along with the objects that depends on it, i.e. the foreign keys
+// of the referencing tables.
 func DropTables(ctx context.Context, conn Connection, tableName, schemaName string) error {
-	_, err := conn.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s.%s", schemaName, tableName))
+	_, err := conn.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s.%s CASCADE", schemaName, tableName))
 	return err
 }
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"reflect"
+	"time"
+)
+
+const (
+	// MaxParameters is the maximum number of parameters of a single statement allowed by the postgres
+	// extended query protocol.
+	MaxParameters = 65535
+	// InArrayThreshold is the number of the IN filter values above which the values are sent
+	// as a single array parameter.
+	InArrayThreshold = 1000
+)
+
+// ChunkSize gets the number of rows with 'rowParameters' parameters each, that fits into a single statement
+// with 'reserved' parameters already used.
+func ChunkSize(rowParameters, reserved int) int {
+	if rowParameters <= 0 {
+		return MaxParameters
+	}
+	size := (MaxParameters - reserved) / rowParameters
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ArrayValue converts the 'values' of the same type into a slice that could be used as a single array parameter.
+// Returns false if the values types differ or there is no postgres array equivalent for their type.
+func ArrayValue(values []interface{}) (interface{}, bool) {
+	if len(values) == 0 || values[0] == nil {
+		return nil, false
+	}
+	t := reflect.TypeOf(values[0])
+	for _, value := range values[1:] {
+		if value == nil || reflect.TypeOf(value) != t {
+			return nil, false
+		}
+	}
+
+	var sliceType reflect.Type
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sliceType = reflect.TypeOf([]int64{})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		sliceType = reflect.TypeOf([]uint64{})
+	case reflect.Float32, reflect.Float64:
+		sliceType = reflect.TypeOf([]float64{})
+	case reflect.String:
+		sliceType = reflect.TypeOf([]string{})
+	case reflect.Bool:
+		sliceType = reflect.TypeOf([]bool{})
+	case reflect.Array:
+		// The uuid values are stored as 16 byte arrays.
+		if t.Len() != 16 || t.Elem().Kind() != reflect.Uint8 {
+			return nil, false
+		}
+		sliceType = reflect.TypeOf([][16]byte{})
+	case reflect.Struct:
+		if !t.ConvertibleTo(timeType) {
+			return nil, false
+		}
+		sliceType = reflect.TypeOf([]time.Time{})
+	default:
+		return nil, false
+	}
+
+	slice := reflect.MakeSlice(sliceType, len(values), len(values))
+	elemType := sliceType.Elem()
+	for i, value := range values {
+		slice.Index(i).Set(reflect.ValueOf(value).Convert(elemType))
+	}
+	return slice.Interface(), true
+}
+
+// TypedArrayValue converts the 'values' of the type 't' into a slice of that type, so that it could be used
+// as a single typed array parameter. The nil values are allowed only for the pointer types and the 16 byte
+// arrays (uuid's) are converted into [16]byte. Returns false if any of the values doesn't match the type 't'.
+func TypedArrayValue(t reflect.Type, values []interface{}) (interface{}, bool) {
+	elemType := t
+	if t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8 {
+		elemType = reflect.TypeOf([16]byte{})
+	}
+	slice := reflect.MakeSlice(reflect.SliceOf(elemType), len(values), len(values))
+	for i, value := range values {
+		if value == nil {
+			if t.Kind() != reflect.Ptr {
+				return nil, false
+			}
+			continue
+		}
+		v := reflect.ValueOf(value)
+		// The numeric values could be converted between their types, i.e. the untyped integer constants.
+		if v.Type() != t && !(isNumeric(v.Kind()) && isNumeric(t.Kind())) {
+			return nil, false
+		}
+		slice.Index(i).Set(v.Convert(elemType))
+	}
+	return slice.Interface(), true
+}
+
+func isNumeric(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
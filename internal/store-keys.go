@@ -5,7 +5,46 @@ var (
 	PostgresVersionKey = pgversion{}
 	// IncrementorKey is the scope's context key used to save current incrementor value.
 	IncrementorKey = incrementorKey{}
+	// FullTableKey is the scope's store key used to allow update or delete queries on the whole table.
+	FullTableKey = fullTableKey{}
+	// IncludeDeletedKey is the scope's store key used to include soft deleted models in the query.
+	IncludeDeletedKey = includeDeletedKey{}
+	// HardDeleteKey is the scope's store key used to delete soft deletable models permanently.
+	HardDeleteKey = hardDeleteKey{}
+	// CursorKey is the scope's store key used to set the keyset pagination cursor.
+	CursorKey = cursorKey{}
+	// PageCursorsKey is the scope's store key used to save the next and previous page cursors.
+	PageCursorsKey = pageCursorsKey{}
+	// InsertDefaultsKey is the scope's store key used to insert the zero value fields as their column defaults.
+	InsertDefaultsKey = insertDefaultsKey{}
+	// ConflictKey is the scope's store key used to set the upsert conflict target and action.
+	ConflictKey = conflictKey{}
+	// UpsertResultsKey is the scope's store key used to save the upsert results of the models.
+	UpsertResultsKey = upsertResultsKey{}
+	// RowLockKey is the scope's store key used to set the row level locking clause of the find query.
+	RowLockKey = rowLockKey{}
+	// ReturnDeletedKey is the scope's store key used to return the rows removed by the delete query.
+	ReturnDeletedKey = returnDeletedKey{}
+	// ReturnUpdatedKey is the scope's store key used to set the fields returned by the update query.
+	ReturnUpdatedKey = returnUpdatedKey{}
+	// UpdateExpressionsKey is the scope's store key used to set the update expressions of the fields.
+	UpdateExpressionsKey = updateExpressionsKey{}
+	// TxOptionsKey is the transaction's context key used to set the postgres specific transaction options.
+	TxOptionsKey = txOptionsKey{}
 )
 
 type pgversion struct{}
 type incrementorKey struct{}
+type fullTableKey struct{}
+type includeDeletedKey struct{}
+type hardDeleteKey struct{}
+type cursorKey struct{}
+type pageCursorsKey struct{}
+type insertDefaultsKey struct{}
+type conflictKey struct{}
+type upsertResultsKey struct{}
+type rowLockKey struct{}
+type returnDeletedKey struct{}
+type returnUpdatedKey struct{}
+type updateExpressionsKey struct{}
+type txOptionsKey struct{}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+)
+
+// LockStrength is the strength of the row level lock taken by the find query.
+type LockStrength int
+
+const (
+	// LockForUpdate locks the selected rows as if they were updated or deleted.
+	LockForUpdate LockStrength = iota + 1
+	// LockForNoKeyUpdate locks the selected rows as if they were updated without changing their keys.
+	LockForNoKeyUpdate
+	// LockForShare takes the shared lock on the selected rows, which blocks their updates and deletes.
+	LockForShare
+)
+
+// String implements fmt.Stringer interface.
+func (l LockStrength) String() string {
+	switch l {
+	case LockForUpdate:
+		return "FOR UPDATE"
+	case LockForNoKeyUpdate:
+		return "FOR NO KEY UPDATE"
+	case LockForShare:
+		return "FOR SHARE"
+	default:
+		return "unknown"
+	}
+}
+
+// LockWait defines the behavior of the row locking query when the rows are already locked.
+type LockWait int
+
+const (
+	// LockWaitDefault waits until the locked rows are released.
+	LockWaitDefault LockWait = iota
+	// LockNoWait fails the query with the ErrLockNotAvailable error if any of the rows is already locked.
+	LockNoWait
+	// LockSkipLocked skips the rows that are already locked.
+	LockSkipLocked
+)
+
+// String implements fmt.Stringer interface.
+func (l LockWait) String() string {
+	switch l {
+	case LockWaitDefault:
+		return ""
+	case LockNoWait:
+		return "NOWAIT"
+	case LockSkipLocked:
+		return "SKIP LOCKED"
+	default:
+		return "unknown"
+	}
+}
+
+// RowLock is the row level locking clause of the find query.
+type RowLock struct {
+	// Strength is the lock strength. It is required.
+	Strength LockStrength
+	// Wait defines what to do if the selected rows are already locked.
+	Wait LockWait
+}
+
+func (r RowLock) validate() error {
+	switch r.Strength {
+	case LockForUpdate, LockForNoKeyUpdate, LockForShare:
+	default:
+		return errors.WrapDetf(query.ErrInvalidParameter, "invalid row lock strength: %d", r.Strength)
+	}
+	switch r.Wait {
+	case LockWaitDefault, LockNoWait, LockSkipLocked:
+	default:
+		return errors.WrapDetf(query.ErrInvalidParameter, "invalid row lock wait policy: %d", r.Wait)
+	}
+	return nil
+}
+
+// writeRowLock writes the scope's row locking clause if the RowLock option is set. The rows could be locked
+// only within a transaction.
+func writeRowLock(s *query.Scope, sb *strings.Builder) error {
+	lock, ok := rowLock(s)
+	if !ok {
+		return nil
+	}
+	if s.Transaction == nil {
+		return errors.WrapDet(query.ErrTxInvalid, "row locking requires the scope to be in a transaction")
+	}
+	if err := lock.validate(); err != nil {
+		return err
+	}
+	sb.WriteRune(' ')
+	sb.WriteString(lock.Strength.String())
+	if lock.Wait != LockWaitDefault {
+		sb.WriteRune(' ')
+		sb.WriteString(lock.Wait.String())
+	}
+	return nil
+}
+
+func rowLock(s *query.Scope) (RowLock, bool) {
+	v, ok := s.StoreGet(internal.RowLockKey)
+	if !ok {
+		return RowLock{}, false
+	}
+	lock, ok := v.(RowLock)
+	return lock, ok
+}
@@ -3,9 +3,12 @@ package migrate
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/repository"
 )
 
 // field tag constraints
@@ -87,20 +90,217 @@ var (
 		DBChecker: existsPrimaryKey,
 	}
 
-	// CForeignKey is the Foreign key constraint.
+	// CForeignKey is the Foreign key constraint. The field must be the belongs to relationship or its foreign key.
 	CForeignKey = &Constraint{Name: "foreign", SQLName: func(field *mapping.StructField) (string, error) {
-		relatedField := field.Relationship().RelatedModelStruct().Primary()
-		relatedModel := relatedField.ModelStruct()
-
-		return fmt.Sprintf("ALTER TABLE %s.%s ADD FOREIGN KEY (%s) REFERENCES %s.%s(%s);",
-			quoteIdentifier(field.ModelStruct().DatabaseSchemaName),
-			quoteIdentifier(field.ModelStruct().DatabaseName),
-			field.DatabaseName,
-			quoteIdentifier(relatedModel.DatabaseSchemaName),
-			quoteIdentifier(relatedModel.DatabaseName),
-			relatedField.DatabaseName,
-		), nil
+		fk, err := belongsToForeignKey(field)
+		if err != nil {
+			return "", err
+		}
+		return fk.definition(), nil
 	},
-		DBChecker: existsForeignKey,
+		DBChecker: func(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, field *mapping.StructField) (bool, error) {
+			fk, err := belongsToForeignKey(field)
+			if err != nil {
+				return false, err
+			}
+			return existsForeignKey(ctx, conn, model, fk.field)
+		},
 	}
 )
+
+// ErrForeignKeyViolation is the error classification for the foreign keys that could not be created, as some of
+// the existing rows reference the rows that doesn't exist.
+var ErrForeignKeyViolation = errors.Wrap(repository.ErrRepository, "foreign key violation")
+
+// Foreign key referential action tags. The actions are defined on the foreign key or the relationship field
+// i.e.: db:"_;on_delete=cascade;on_update=restrict".
+const (
+	// OnDeleteTag is the database field tag that defines the foreign key ON DELETE action.
+	OnDeleteTag = "on_delete"
+	// OnUpdateTag is the database field tag that defines the foreign key ON UPDATE action.
+	OnUpdateTag = "on_update"
+)
+
+// Foreign key referential actions.
+const (
+	ActionNoAction = "NO ACTION"
+	ActionRestrict = "RESTRICT"
+	ActionCascade  = "CASCADE"
+	ActionSetNull  = "SET NULL"
+)
+
+// actionCodes are the pg_constraint codes of the foreign key actions.
+var actionCodes = map[string]string{
+	ActionNoAction: "a",
+	ActionRestrict: "r",
+	ActionCascade:  "c",
+	ActionSetNull:  "n",
+}
+
+// foreignKey is the foreign key constraint of the 'field' column that references the 'references' column.
+type foreignKey struct {
+	field      *mapping.StructField
+	references *mapping.StructField
+	// onDelete and onUpdate are the referential actions of the foreign key.
+	onDelete, onUpdate string
+}
+
+// name gets the foreign key constraint name.
+func (f *foreignKey) name() string {
+	return fmt.Sprintf("fk_%s_%s", f.field.ModelStruct().DatabaseName, f.field.DatabaseName)
+}
+
+// definition gets the foreign key constraint definition.
+func (f *foreignKey) definition() string {
+	return f.addDefinition("")
+}
+
+// notValidDefinition gets the foreign key constraint definition that doesn't check the existing rows. The constraint
+// needs to be validated with the validateDefinition statement.
+func (f *foreignKey) notValidDefinition() string {
+	return f.addDefinition(" NOT VALID")
+}
+
+// validateDefinition gets the statement that checks if the existing rows satisfy the foreign key constraint.
+func (f *foreignKey) validateDefinition() string {
+	model := f.field.ModelStruct()
+	return fmt.Sprintf("ALTER TABLE %s.%s VALIDATE CONSTRAINT %s;",
+		quoteIdentifier(model.DatabaseSchemaName),
+		quoteIdentifier(model.DatabaseName),
+		quoteIdentifier(f.name()),
+	)
+}
+
+func (f *foreignKey) addDefinition(suffix string) string {
+	model, related := f.field.ModelStruct(), f.references.ModelStruct()
+	return fmt.Sprintf("ALTER TABLE %s.%s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s.%s (%s) ON DELETE %s ON UPDATE %s%s;",
+		quoteIdentifier(model.DatabaseSchemaName),
+		quoteIdentifier(model.DatabaseName),
+		quoteIdentifier(f.name()),
+		f.field.DatabaseName,
+		quoteIdentifier(related.DatabaseSchemaName),
+		quoteIdentifier(related.DatabaseName),
+		f.references.DatabaseName,
+		f.onDelete,
+		f.onUpdate,
+		suffix,
+	)
+}
+
+// matches checks if the existing 'constraint' is this foreign key.
+func (f *foreignKey) matches(constraint *constraintSchema) bool {
+	related := f.references.ModelStruct()
+	return constraint.kind == constraintForeign &&
+		len(constraint.columns) == 1 && constraint.columns[0] == f.field.DatabaseName &&
+		constraint.refSchema == related.DatabaseSchemaName && constraint.refTable == related.DatabaseName &&
+		len(constraint.refColumns) == 1 && constraint.refColumns[0] == f.references.DatabaseName &&
+		constraint.onDelete == actionCodes[f.onDelete] && constraint.onUpdate == actionCodes[f.onUpdate]
+}
+
+// modelsForeignKeys gets the foreign keys defined by the relationships of the 'models', mapped by the models
+// that contains the foreign key columns.
+func modelsForeignKeys(models ...*mapping.ModelStruct) map[*mapping.ModelStruct][]*foreignKey {
+	foreignKeys := map[*mapping.ModelStruct][]*foreignKey{}
+	add := func(relationField, field, references *mapping.StructField) {
+		if field == nil || references == nil || field.DatabaseSkip() || references.DatabaseSkip() {
+			return
+		}
+		model := field.ModelStruct()
+		for _, fk := range foreignKeys[model] {
+			if fk.field == field {
+				// The actions might be defined on any of the relationships using the foreign key.
+				if fk.onDelete == ActionNoAction {
+					fk.onDelete = foreignKeyAction(OnDeleteTag, relationField)
+				}
+				if fk.onUpdate == ActionNoAction {
+					fk.onUpdate = foreignKeyAction(OnUpdateTag, relationField)
+				}
+				return
+			}
+		}
+		foreignKeys[model] = append(foreignKeys[model], &foreignKey{
+			field:      field,
+			references: references,
+			onDelete:   foreignKeyAction(OnDeleteTag, field, relationField),
+			onUpdate:   foreignKeyAction(OnUpdateTag, field, relationField),
+		})
+	}
+	for _, model := range models {
+		for _, relationField := range model.RelationFields() {
+			relation := relationField.Relationship()
+			switch relation.Kind() {
+			case mapping.RelBelongsTo:
+				add(relationField, relation.ForeignKey(), relation.RelatedModelStruct().Primary())
+			case mapping.RelHasOne, mapping.RelHasMany:
+				add(relationField, relation.ForeignKey(), model.Primary())
+			case mapping.RelMany2Many:
+				add(relationField, relation.ForeignKey(), model.Primary())
+				add(relationField, relation.ManyToManyForeignKey(), relation.RelatedModelStruct().Primary())
+			}
+		}
+	}
+	return foreignKeys
+}
+
+// belongsToForeignKey gets the foreign key of the belongs to relationship 'field' or the foreign key 'field'
+// of the model's belongs to relationship.
+func belongsToForeignKey(field *mapping.StructField) (*foreignKey, error) {
+	for _, relationField := range field.ModelStruct().RelationFields() {
+		relation := relationField.Relationship()
+		if relation.Kind() != mapping.RelBelongsTo || (relationField != field && relation.ForeignKey() != field) {
+			continue
+		}
+		return &foreignKey{
+			field:      relation.ForeignKey(),
+			references: relation.RelatedModelStruct().Primary(),
+			onDelete:   foreignKeyAction(OnDeleteTag, relation.ForeignKey(), relationField),
+			onUpdate:   foreignKeyAction(OnUpdateTag, relation.ForeignKey(), relationField),
+		}, nil
+	}
+	return nil, errors.WrapDetf(mapping.ErrMapping, "field: '%s' is neither a belongs to relationship nor its foreign key", field)
+}
+
+// foreignKeyAction gets the referential action defined by the 'tag' on the first of the 'fields' that defines it.
+// The actions are validated when the models are prepared.
+func foreignKeyAction(tag string, fields ...*mapping.StructField) string {
+	for _, field := range fields {
+		if action, ok := fieldForeignKeyAction(field, tag); ok {
+			return action
+		}
+	}
+	return ActionNoAction
+}
+
+func fieldForeignKeyAction(field *mapping.StructField, tag string) (string, bool) {
+	for _, fieldTag := range field.DatabaseUnknownTags {
+		if fieldTag.Key != tag || len(fieldTag.Values) != 1 {
+			continue
+		}
+		action := strings.ToUpper(strings.Replace(strings.TrimSpace(fieldTag.Values[0]), "_", " ", -1))
+		if _, ok := actionCodes[action]; ok {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// checkForeignKeyActions checks if the referential action tags of the model's fields are valid.
+func checkForeignKeyActions(model *mapping.ModelStruct) error {
+	for _, field := range model.StructFields() {
+		for _, tag := range []string{OnDeleteTag, OnUpdateTag} {
+			for _, fieldTag := range field.DatabaseUnknownTags {
+				if fieldTag.Key != tag {
+					continue
+				}
+				action, ok := fieldForeignKeyAction(field, tag)
+				if !ok {
+					return errors.WrapDetf(mapping.ErrMapping, "model: '%s' field: '%s' invalid '%s' action: '%s'", model, field, tag, strings.Join(fieldTag.Values, ","))
+				}
+				if action == ActionSetNull && field.DatabaseNotNull() {
+					return errors.WrapDetf(mapping.ErrMapping, "model: '%s' not null field: '%s' cannot use the '%s' action: '%s'", model, field, tag, action)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -40,17 +40,6 @@ func existsTable(ctx context.Context, conn internal.Connection, m *mapping.Model
 	return count > 0, nil
 }
 
-// existsColumn checks if the provided table has given column set in the database.
-func existsColumn(ctx context.Context, conn internal.Connection, m *mapping.ModelStruct, field *mapping.StructField) (bool, error) {
-	var count int
-	err := conn.QueryRow(ctx, "SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2 AND table_schema = $3", m.DatabaseName, field.DatabaseName, m.DatabaseSchemaName).Scan(&count)
-	if err != nil {
-		log.Debugf("Querying column for the table: '%s' failed: %v", m.DatabaseName, err)
-		return false, err
-	}
-	return count > 0, nil
-}
-
 // existsIndex checks if the following table has provided index.
 func existsIndex(ctx context.Context, conn internal.Connection, m *mapping.ModelStruct, i *mapping.DatabaseIndex) (bool, error) {
 	var count int
@@ -73,10 +62,15 @@ func existsPrimaryKey(ctx context.Context, conn internal.Connection, m *mapping.
 	return count > 0, nil
 }
 
-// existsForeignKey checks if the foreign key exists.
+// existsForeignKey checks if the foreign key constraint on the 'field' column exists.
 func existsForeignKey(ctx context.Context, conn internal.Connection, m *mapping.ModelStruct, field *mapping.StructField) (bool, error) {
 	var count int
-	err := conn.QueryRow(ctx, "SELECT count(*) from pq_foreign_keys_view WHERE table_name = $1 and column_name = $2", m.DatabaseName, field.DatabaseName).Scan(&count)
+	err := conn.QueryRow(ctx, `SELECT count(*) FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = ANY(con.conkey)
+WHERE con.contype = 'f' AND n.nspname = $1 AND c.relname = $2 AND a.attname = $3`,
+		m.DatabaseSchemaName, m.DatabaseName, field.DatabaseName).Scan(&count)
 	if err != nil {
 		log.Debugf("Querying foreign keys for the table: '%s' failed: %v", m.DatabaseName, err)
 		return false, err
@@ -84,26 +78,6 @@ func existsForeignKey(ctx context.Context, conn internal.Connection, m *mapping.
 	return count > 0, nil
 }
 
-// createForeignKeysView creates a sql View for the foreign keys per table.
-func createForeignKeysView(ctx context.Context, conn internal.Connection) {
-	query := `CREATE OR REPLACE VIEW pq_foreign_keys_view AS
-SELECT
-    tc.table_name, kcu.column_name,
-    ccu.table_name AS foreign_table_name,
-    ccu.column_name AS foreign_column_name
-FROM
-    information_schema.table_constraints AS tc
-    JOIN information_schema.key_column_usage 
-        AS kcu ON tc.constraint_name = kcu.constraint_name
-    JOIN information_schema.constraint_column_usage 
-        AS ccu ON ccu.constraint_name = tc.constraint_name
-WHERE constraint_type = 'FOREIGN KEY';`
-
-	if _, err := conn.Exec(ctx, query); err != nil {
-		log.Errorf("Creating Foreign Keys view failed: %v", err)
-	}
-}
-
 // HasUniqueConstraint checks if the table contains constraint.
 func HasUniqueConstraint(ctx context.Context, conn internal.Connection, m *mapping.ModelStruct, field *mapping.StructField) (bool, error) {
 	var count int
@@ -127,23 +101,29 @@ func HasNotNullConstraint(ctx context.Context, conn internal.Connection, model *
 }
 
 func dropNotNull(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, field *mapping.StructField) error {
-	_, err := conn.Exec(ctx,
-		fmt.Sprintf("ALTER TABLE %s.%s ALTER %s DROP NOT NULL",
-			quoteIdentifier(model.DatabaseSchemaName),
-			quoteIdentifier(model.DatabaseName),
-			field.DatabaseName,
-		),
-	)
+	_, err := conn.Exec(ctx, dropNotNullDefinition(model, field.DatabaseName))
 	return err
 }
 
 func dropUnique(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, field *mapping.StructField) error {
-	_, err := conn.Exec(ctx,
-		fmt.Sprintf("ALTER TABLE %s.%s DROP CONSTRAINT %s",
-			quoteIdentifier(model.DatabaseSchemaName),
-			quoteIdentifier(model.DatabaseName),
-			uniqueConstraintName(field),
-		),
-	)
+	_, err := conn.Exec(ctx, dropConstraintDefinition(model, uniqueConstraintName(field)))
 	return err
 }
+
+func dropNotNullDefinition(model *mapping.ModelStruct, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s.%s ALTER %s DROP NOT NULL",
+		quoteIdentifier(model.DatabaseSchemaName),
+		quoteIdentifier(model.DatabaseName),
+		column,
+	)
+}
+
+// dropConstraintDefinition gets the definition that drops the 'model' table 'constraint'. The constraint name
+// is quoted by the function.
+func dropConstraintDefinition(model *mapping.ModelStruct, constraint string) string {
+	return fmt.Sprintf("ALTER TABLE %s.%s DROP CONSTRAINT %s",
+		quoteIdentifier(model.DatabaseSchemaName),
+		quoteIdentifier(model.DatabaseName),
+		quoteIdentifier(constraint),
+	)
+}
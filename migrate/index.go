@@ -17,7 +17,12 @@ func migrateIndex(ctx context.Context, conn internal.Connection, model *mapping.
 	if exists {
 		return nil
 	}
+	_, err = conn.Exec(ctx, indexDefinition(model, index))
+	return err
+}
 
+// indexDefinition gets the model's index definition.
+func indexDefinition(model *mapping.ModelStruct, index *mapping.DatabaseIndex) string {
 	sb := strings.Builder{}
 	sb.WriteString("CREATE ")
 	if index.Unique {
@@ -25,8 +30,10 @@ func migrateIndex(ctx context.Context, conn internal.Connection, model *mapping.
 	}
 	sb.WriteString("INDEX ")
 	sb.WriteString(indexPrefixer(index.Name))
-	sb.WriteString("ON ")
-	sb.WriteString(model.DatabaseName)
+	sb.WriteString(" ON ")
+	sb.WriteString(quoteIdentifier(model.DatabaseSchemaName))
+	sb.WriteRune('.')
+	sb.WriteString(quoteIdentifier(model.DatabaseName))
 	if index.Type != BTreeIndex {
 		sb.WriteString(" USING ")
 		sb.WriteString(index.Type)
@@ -39,8 +46,7 @@ func migrateIndex(ctx context.Context, conn internal.Connection, model *mapping.
 		}
 	}
 	sb.WriteString(");")
-	_, err = conn.Exec(ctx, sb.String())
-	return err
+	return sb.String()
 }
 
 func newIndexName(model *mapping.ModelStruct, field *mapping.StructField, index *mapping.DatabaseIndex) string {
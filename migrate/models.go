@@ -7,25 +7,81 @@ import (
 
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 )
 
+// StaleColumnAction defines what to do with the table columns that no longer exist on the model.
+type StaleColumnAction int
+
+const (
+	// StaleColumnsKeep leaves the stale columns unchanged.
+	StaleColumnsKeep StaleColumnAction = iota
+	// StaleColumnsRename renames the stale columns by adding the 'nrn_stale_' prefix, so that their data is kept
+	// but these no longer collide with the new columns.
+	StaleColumnsRename
+	// StaleColumnsDrop drops the stale columns along with their data.
+	StaleColumnsDrop
+)
+
+// staleColumnPrefix is the name prefix of the renamed stale columns.
+const staleColumnPrefix = "nrn_stale_"
+
+// Options are the models migration options.
+type Options struct {
+	// AllowDestructive allows the migration to rename or drop the stale columns and to apply the narrowing or lossy
+	// column type changes. Otherwise such type changes are skipped.
+	AllowDestructive bool
+	// StaleColumns defines what to do with the table columns that no longer exist on the model.
+	// Any action other than the StaleColumnsKeep requires the AllowDestructive option.
+	StaleColumns StaleColumnAction
+}
+
+func (o Options) validate() error {
+	switch o.StaleColumns {
+	case StaleColumnsKeep:
+	case StaleColumnsRename, StaleColumnsDrop:
+		if !o.AllowDestructive {
+			return errors.WrapDet(ErrDestructiveMigration, "renaming or dropping stale columns requires destructive migrations to be allowed")
+		}
+	default:
+		return errors.WrapDetf(errors.ErrInternal, "invalid stale columns action: %d", o.StaleColumns)
+	}
+	return nil
+}
+
 // Models inserts model's definitions, indexes and constraints if not exists.
 // The models needs to be prepared earlier.
 func Models(ctx context.Context, conn internal.Connection, models ...*mapping.ModelStruct) error {
+	return ModelsWithOptions(ctx, conn, Options{}, models...)
+}
+
+// ModelsWithOptions inserts model's definitions, indexes and constraints if not exists. The data types of
+// the existing columns are changed to match the model's fields. The stale columns are handled as defined
+// in the 'options'. The tables are migrated so that the referenced tables are created first and the relationship
+// foreign keys are created after all the tables exist. The models referenced by the foreign keys needs to be
+// migrated along or their tables needs to exist already. The models needs to be prepared earlier.
+func ModelsWithOptions(ctx context.Context, conn internal.Connection, options Options, models ...*mapping.ModelStruct) error {
+	if err := options.validate(); err != nil {
+		return err
+	}
+	foreignKeys := modelsForeignKeys(models...)
+	models = orderModels(foreignKeys, models...)
 	for _, model := range models {
-		if err := migrateModel(ctx, conn, model); err != nil {
+		if err := migrateTable(ctx, conn, model, options); err != nil {
+			return err
+		}
+	}
+	for _, model := range models {
+		if err := migrateModel(ctx, conn, model, foreignKeys[model]); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func migrateModel(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct) error {
-	if err := migrateTable(ctx, conn, model); err != nil {
-		return err
-	}
-	if err := migrateConstraints(ctx, conn, model); err != nil {
+func migrateModel(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, foreignKeys []*foreignKey) error {
+	if err := migrateConstraints(ctx, conn, model, foreignKeys); err != nil {
 		return err
 	}
 	for _, index := range model.DatabaseIndexes() {
@@ -36,7 +92,33 @@ func migrateModel(ctx context.Context, conn internal.Connection, model *mapping.
 	return nil
 }
 
-func migrateConstraints(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct) error {
+// orderModels orders the 'models' so that the models referenced by the 'foreignKeys' precede the models
+// that reference them. The circular references are broken at the model that comes first in the input 'models'.
+func orderModels(foreignKeys map[*mapping.ModelStruct][]*foreignKey, models ...*mapping.ModelStruct) []*mapping.ModelStruct {
+	migrated := make(map[*mapping.ModelStruct]bool, len(models))
+	for _, model := range models {
+		migrated[model] = false
+	}
+	ordered := make([]*mapping.ModelStruct, 0, len(models))
+	var visit func(model *mapping.ModelStruct)
+	visit = func(model *mapping.ModelStruct) {
+		visited, ok := migrated[model]
+		if !ok || visited {
+			return
+		}
+		migrated[model] = true
+		for _, fk := range foreignKeys[model] {
+			visit(fk.references.ModelStruct())
+		}
+		ordered = append(ordered, model)
+	}
+	for _, model := range models {
+		visit(model)
+	}
+	return ordered
+}
+
+func migrateConstraints(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, foreignKeys []*foreignKey) error {
 	for _, field := range model.Fields() {
 		if field.DatabaseSkip() {
 			continue
@@ -79,10 +161,34 @@ func migrateConstraints(ctx context.Context, conn internal.Connection, model *ma
 			}
 		}
 	}
+	return migrateForeignKeys(ctx, conn, model, foreignKeys)
+}
+
+// migrateForeignKeys creates the model's relationship foreign keys, recreates the ones with changed referential
+// actions and drops the automatically created foreign keys that are no longer defined.
+func migrateForeignKeys(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, foreignKeys []*foreignKey) error {
+	schema := &tableSchema{constraints: map[string]*constraintSchema{}}
+	if err := readConstraints(ctx, conn, model, schema); err != nil {
+		return err
+	}
+	plan := &Plan{}
+	planForeignKeys(plan, model, schema, foreignKeys)
+	for _, statement := range plan.Statements {
+		log.Debugf("Migrating table: %s foreign keys, DB Query: \n%s", model.DatabaseName, statement.SQL)
+		if _, err := conn.Exec(ctx, statement.SQL); err != nil {
+			for _, fk := range foreignKeys {
+				if statement.SQL == fk.validateDefinition() {
+					return errors.WrapDetf(ErrForeignKeyViolation, "model: '%s' column: '%s' contains values not existing in the table: '%s': %v",
+						model, fk.field.DatabaseName, fk.references.ModelStruct().DatabaseName, err)
+				}
+			}
+			return err
+		}
+	}
 	return nil
 }
 
-func migrateTable(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct) error {
+func migrateTable(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, options Options) error {
 	var databaseFields int
 	for _, field := range model.Fields() {
 		if !field.DatabaseSkip() {
@@ -111,43 +217,99 @@ func migrateTable(ctx context.Context, conn internal.Connection, model *mapping.
 		return nil
 	}
 
+	schema := &tableSchema{}
+	if err = readColumns(ctx, conn, model, schema); err != nil {
+		return err
+	}
 	for _, field := range model.Fields() {
 		if field.DatabaseSkip() {
 			continue
 		}
-		columnExists, err := existsColumn(ctx, conn, model, field)
+		dt, err := findDataType(field)
 		if err != nil {
 			return err
 		}
-		if columnExists {
+		column, columnExists := schema.column(field.DatabaseName)
+		if !columnExists {
+			query := addColumnDefinition(model, field, dt)
+			log.Debugf("Updating table: %s column: %s, DB Query: \n%s", model.DatabaseName, field.DatabaseName, query)
+			if _, err := conn.Exec(ctx, query); err != nil {
+				return err
+			}
 			continue
 		}
-		dt, err := findDataType(field)
-		if err != nil {
-			return err
+		if _, ok := dt.(ExternalDataTyper); ok {
+			continue
 		}
-
-		if dtt, ok := dt.(ExternalDataTyper); ok {
-			if _, err := conn.Exec(ctx, dtt.ExternalFunction(field)); err != nil {
-				return err
+		if dataType := normalizeType(dt.GetName()); column.dataType != dataType {
+			if !options.AllowDestructive && !isWideningType(column.dataType, dataType) {
+				log.Warningf("Skipping table: %s column: %s type change from: %s to: %s - destructive migrations are not allowed", model.DatabaseName, field.DatabaseName, column.dataType, dataType)
+				continue
 			}
-		} else {
-			query := fmt.Sprintf("ALTER TABLE %s.%s ADD %s %s;",
-				quoteIdentifier(model.DatabaseSchemaName),
-				model.DatabaseName,
-				field.DatabaseName,
-				dt.GetName(),
-			)
-
-			log.Debugf("Updating table: %s column: %s, DB Query: \n%s", model.DatabaseName, field.DatabaseName, query)
+			query := alterColumnTypeDefinition(model, field.DatabaseName, dataType)
+			log.Debugf("Changing table: %s column: %s type from: %s to: %s, DB Query: \n%s", model.DatabaseName, field.DatabaseName, column.dataType, dataType, query)
 			if _, err := conn.Exec(ctx, query); err != nil {
 				return err
 			}
 		}
 	}
+	return migrateStaleColumns(ctx, conn, model, schema, options)
+}
+
+// migrateStaleColumns renames or drops the table columns that no longer exist on the model.
+func migrateStaleColumns(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, schema *tableSchema, options Options) error {
+	if options.StaleColumns == StaleColumnsKeep {
+		return nil
+	}
+	for _, column := range schema.columns {
+		if hasColumnField(model, column.name) {
+			continue
+		}
+		var query string
+		switch options.StaleColumns {
+		case StaleColumnsRename:
+			if strings.HasPrefix(column.name, staleColumnPrefix) {
+				continue
+			}
+			query = renameColumnDefinition(model, column.name, staleColumnPrefix+column.name)
+		case StaleColumnsDrop:
+			query = dropColumnDefinition(model, column.name)
+		}
+		log.Debugf("Migrating table: %s stale column: %s, DB Query: \n%s", model.DatabaseName, column.name, query)
+		if _, err := conn.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func renameColumnDefinition(model *mapping.ModelStruct, column, name string) string {
+	return fmt.Sprintf("ALTER TABLE %s.%s RENAME COLUMN %s TO %s;",
+		quoteIdentifier(model.DatabaseSchemaName),
+		quoteIdentifier(model.DatabaseName),
+		quoteIdentifier(column),
+		quoteIdentifier(name),
+	)
+}
+
+// addColumnDefinition gets the definition of the model's 'field' column with the data type 'dt'.
+func addColumnDefinition(model *mapping.ModelStruct, field *mapping.StructField, dt DataTyper) string {
+	if dtt, ok := dt.(ExternalDataTyper); ok {
+		return dtt.ExternalFunction(field)
+	}
+	columnType := dt.GetName()
+	if IsVersionField(field) {
+		// The default version is set for all the existing rows.
+		columnType += versionDefault
+	}
+	return fmt.Sprintf("ALTER TABLE %s.%s ADD %s %s;",
+		quoteIdentifier(model.DatabaseSchemaName),
+		model.DatabaseName,
+		field.DatabaseName,
+		columnType,
+	)
+}
+
 // PrepareModels prepares database models
 func PrepareModels(models ...*mapping.ModelStruct) error {
 	for _, model := range models {
@@ -180,6 +342,13 @@ func prepareModel(model *mapping.ModelStruct) error {
 		}
 	}
 
+	if err := checkVersionField(model); err != nil {
+		return err
+	}
+	if err := checkForeignKeyActions(model); err != nil {
+		return err
+	}
+
 	for _, index := range model.DatabaseIndexes() {
 		for _, parameter := range index.Parameters {
 			switch parameter {
@@ -234,6 +403,9 @@ func tableDefinitions(model *mapping.ModelStruct) ([]string, error) {
 		sb.WriteString(field.DatabaseName)
 		sb.WriteString(" ")
 		sb.WriteString(dt.GetName())
+		if IsVersionField(field) {
+			sb.WriteString(versionDefault)
+		}
 
 		if i < inlineColumns-1 {
 			sb.WriteString(",")
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 )
 
@@ -79,3 +80,12 @@ func tCtrl(t *testing.T, models ...mapping.Model) *mapping.ModelMap {
 
 	return m
 }
+
+// TestOptionsValidate tests the validation of the migration options.
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, Options{}.validate())
+	assert.NoError(t, Options{AllowDestructive: true, StaleColumns: StaleColumnsDrop}.validate())
+
+	err := Options{StaleColumns: StaleColumnsDrop}.validate()
+	assert.True(t, errors.Is(err, ErrDestructiveMigration))
+}
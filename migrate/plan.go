@@ -0,0 +1,308 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/repository"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+// ErrDestructiveMigration is the error classification for the migration plans that contains destructive statements
+// which were not allowed.
+var ErrDestructiveMigration = errors.Wrap(repository.ErrRepository, "destructive migration")
+
+// Statement is a single DDL statement of the migration plan.
+type Statement struct {
+	// SQL is the statement query.
+	SQL string
+	// Destructive defines if the statement could lose the existing data, i.e. by dropping or converting the column.
+	Destructive bool
+}
+
+// Plan is the ordered list of the DDL statements that migrates the database schema to the models definitions.
+type Plan struct {
+	Statements []*Statement
+}
+
+// IsEmpty checks if the schema is already up to date.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Statements) == 0
+}
+
+// IsDestructive checks if any of the plan statements is destructive.
+func (p *Plan) IsDestructive() bool {
+	for _, statement := range p.Statements {
+		if statement.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+// String implements fmt.Stringer interface. It gets the plan statements, so that these could be reviewed
+// before being applied. The destructive statements are preceded by the comment.
+func (p *Plan) String() string {
+	sb := &strings.Builder{}
+	for _, statement := range p.Statements {
+		if statement.Destructive {
+			sb.WriteString("-- destructive\n")
+		}
+		sb.WriteString(statement.SQL)
+		sb.WriteRune('\n')
+	}
+	return sb.String()
+}
+
+// Apply executes the plan statements in order using provided connection. If the plan is destructive and
+// the destructive statements are not allowed, none of the statements is executed. In order to apply the plan
+// atomically the 'conn' should be a transaction.
+func (p *Plan) Apply(ctx context.Context, conn internal.Connection, allowDestructive bool) error {
+	if !allowDestructive && p.IsDestructive() {
+		return errors.WrapDet(ErrDestructiveMigration, "migration plan contains destructive statements")
+	}
+	for _, statement := range p.Statements {
+		log.Debugf("Migrate Query: \n%s", statement.SQL)
+		if _, err := conn.Exec(ctx, statement.SQL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Plan) add(sql string, destructive bool) {
+	p.Statements = append(p.Statements, &Statement{SQL: sql, Destructive: destructive})
+}
+
+// PlanModels compares the existing database schema with the models definitions and gets the plan that migrates
+// the schema. The plan covers the tables, columns, their data types, not null and unique constraints, primary keys,
+// indexes and the relationship foreign keys. The tables and columns are planned first, then the constraints and
+// indexes and finally the foreign keys, so that all referenced tables exist. The models needs to be prepared earlier.
+func PlanModels(ctx context.Context, conn internal.Connection, models ...*mapping.ModelStruct) (*Plan, error) {
+	plan := &Plan{}
+	schemas := map[*mapping.ModelStruct]*tableSchema{}
+	var planned []*mapping.ModelStruct
+	for _, model := range models {
+		if !hasDatabaseFields(model) {
+			continue
+		}
+		schema, err := readTableSchema(ctx, conn, model)
+		if err != nil {
+			return nil, err
+		}
+		if err = planTable(plan, model, schema); err != nil {
+			return nil, err
+		}
+		schemas[model] = schema
+		planned = append(planned, model)
+	}
+
+	for _, model := range planned {
+		planConstraints(plan, model, schemas[model])
+		planIndexes(plan, model, schemas[model])
+	}
+
+	foreignKeys := modelsForeignKeys(planned...)
+	for _, model := range orderModels(foreignKeys, planned...) {
+		planForeignKeys(plan, model, schemas[model], foreignKeys[model])
+	}
+	return plan, nil
+}
+
+// planTable plans the model's table creation or its columns changes. The 'schema' of the created table is filled
+// with its columns.
+func planTable(plan *Plan, model *mapping.ModelStruct, schema *tableSchema) error {
+	if !schema.exists {
+		definitions, err := tableDefinitions(model)
+		if err != nil {
+			return err
+		}
+		for _, definition := range definitions {
+			plan.add(definition, false)
+		}
+		for _, field := range model.Fields() {
+			if !field.DatabaseSkip() {
+				schema.columns = append(schema.columns, &columnSchema{name: field.DatabaseName})
+			}
+		}
+		return nil
+	}
+
+	for _, field := range model.Fields() {
+		if field.DatabaseSkip() {
+			continue
+		}
+		dt, err := findDataType(field)
+		if err != nil {
+			return err
+		}
+		column, ok := schema.column(field.DatabaseName)
+		if !ok {
+			plan.add(addColumnDefinition(model, field, dt), false)
+			schema.columns = append(schema.columns, &columnSchema{name: field.DatabaseName})
+			continue
+		}
+		if _, ok := dt.(ExternalDataTyper); ok {
+			continue
+		}
+		if dataType := normalizeType(dt.GetName()); column.dataType != dataType {
+			plan.add(alterColumnTypeDefinition(model, field.DatabaseName, dataType), !isWideningType(column.dataType, dataType))
+		}
+	}
+
+	for _, column := range schema.columns {
+		if !hasColumnField(model, column.name) {
+			plan.add(dropColumnDefinition(model, column.name), true)
+		}
+	}
+	return nil
+}
+
+// planConstraints plans the model's primary key, not null and unique constraints changes.
+func planConstraints(plan *Plan, model *mapping.ModelStruct, schema *tableSchema) {
+	for _, field := range model.Fields() {
+		if field.DatabaseSkip() {
+			continue
+		}
+		if field.Kind() == mapping.KindPrimary {
+			if len(schema.constraintsOf(constraintPrimary)) == 0 {
+				definition, _ := CPrimaryKey.SQLName(field)
+				plan.add(definition, false)
+			}
+			continue
+		}
+		column, ok := schema.column(field.DatabaseName)
+		if !ok {
+			continue
+		}
+		switch {
+		case field.DatabaseNotNull() && !column.notNull:
+			definition, _ := CNotNull.SQLName(field)
+			plan.add(definition, false)
+		case !field.DatabaseNotNull() && column.notNull:
+			plan.add(dropNotNullDefinition(model, field.DatabaseName)+";", false)
+		}
+		_, hasUnique := schema.constraints[uniqueConstraintName(field)]
+		switch {
+		case field.DatabaseUnique() && !hasUnique:
+			definition, _ := CUnique.SQLName(field)
+			plan.add(definition, false)
+		case !field.DatabaseUnique() && hasUnique:
+			plan.add(dropConstraintDefinition(model, uniqueConstraintName(field))+";", false)
+		}
+	}
+}
+
+// planIndexes plans the creation of the model's indexes and removal of the automatically created indexes
+// that are no longer defined.
+func planIndexes(plan *Plan, model *mapping.ModelStruct, schema *tableSchema) {
+	defined := map[string]struct{}{}
+	for _, index := range model.DatabaseIndexes() {
+		name := indexPrefixer(index.Name)
+		defined[name] = struct{}{}
+		if _, ok := schema.indexes[name]; !ok {
+			plan.add(indexDefinition(model, index), false)
+		}
+	}
+	for _, name := range sortedKeys(schema.indexes) {
+		if _, ok := defined[name]; !ok && strings.HasPrefix(name, indexPrefixer("")) {
+			plan.add(fmt.Sprintf("DROP INDEX %s.%s;", quoteIdentifier(model.DatabaseSchemaName), quoteIdentifier(name)), false)
+		}
+	}
+}
+
+// planForeignKeys plans the creation of the model's relationship foreign keys and removal of the automatically
+// created foreign keys that are no longer defined. The foreign keys with changed referential actions are dropped
+// and created again.
+func planForeignKeys(plan *Plan, model *mapping.ModelStruct, schema *tableSchema, foreignKeys []*foreignKey) {
+	existing := schema.constraintsOf(constraintForeign)
+	matched := map[string]struct{}{}
+	var missing, notValidated []*foreignKey
+	for _, fk := range foreignKeys {
+		var found bool
+		for _, constraint := range existing {
+			if fk.matches(constraint) {
+				matched[constraint.name] = struct{}{}
+				found = true
+				if !constraint.validated {
+					notValidated = append(notValidated, fk)
+				}
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, fk)
+		}
+	}
+	var stale []string
+	for _, constraint := range existing {
+		if _, ok := matched[constraint.name]; !ok && strings.HasPrefix(constraint.name, "fk_") {
+			stale = append(stale, constraint.name)
+		}
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		plan.add(dropConstraintDefinition(model, name)+";", false)
+	}
+	// The foreign keys are added without checking the existing rows, so that the validation could fail with the error
+	// pointing to the foreign key, and without blocking the writes of the referenced table.
+	for _, fk := range missing {
+		plan.add(fk.notValidDefinition(), false)
+		notValidated = append(notValidated, fk)
+	}
+	for _, fk := range notValidated {
+		plan.add(fk.validateDefinition(), false)
+	}
+}
+
+func alterColumnTypeDefinition(model *mapping.ModelStruct, column, dataType string) string {
+	return fmt.Sprintf("ALTER TABLE %s.%s ALTER COLUMN %s TYPE %s USING %s::%s;",
+		quoteIdentifier(model.DatabaseSchemaName),
+		quoteIdentifier(model.DatabaseName),
+		quoteIdentifier(column),
+		dataType,
+		quoteIdentifier(column),
+		dataType,
+	)
+}
+
+func dropColumnDefinition(model *mapping.ModelStruct, column string) string {
+	return fmt.Sprintf("ALTER TABLE %s.%s DROP COLUMN %s;",
+		quoteIdentifier(model.DatabaseSchemaName),
+		quoteIdentifier(model.DatabaseName),
+		quoteIdentifier(column),
+	)
+}
+
+func hasDatabaseFields(model *mapping.ModelStruct) bool {
+	for _, field := range model.Fields() {
+		if !field.DatabaseSkip() {
+			return true
+		}
+	}
+	return false
+}
+
+func hasColumnField(model *mapping.ModelStruct, column string) bool {
+	for _, field := range model.Fields() {
+		if !field.DatabaseSkip() && field.DatabaseName == column {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,176 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+)
+
+// TestNormalizeType tests the normalization of the data type names.
+func TestNormalizeType(t *testing.T) {
+	for name, expected := range map[string]string{
+		"serial":                      "integer",
+		"bigserial":                   "bigint",
+		"varchar(20)":                 "character varying(20)",
+		"character varying(20)":       "character varying(20)",
+		"timestamp":                   "timestamp without time zone",
+		"timestamp with time zone":    "timestamp with time zone",
+		"timestamp(3) with time zone": "timestamp(3) with time zone",
+		"timestamptz":                 "timestamp with time zone",
+		"integer[3]":                  "integer[]",
+		"decimal(10, 2)":              "numeric(10,2)",
+		"bytea()":                     "bytea",
+		"double precision":            "double precision",
+	} {
+		assert.Equal(t, expected, normalizeType(name), name)
+	}
+}
+
+// TestPlanTable tests the planning of the table changes.
+func TestPlanTable(t *testing.T) {
+	model := &BasicModel{}
+	m := tCtrl(t, model)
+
+	mStruct, ok := m.GetModelStruct(model)
+	require.True(t, ok)
+
+	t.Run("NotExists", func(t *testing.T) {
+		plan := &Plan{}
+		schema := &tableSchema{constraints: map[string]*constraintSchema{}, indexes: map[string]struct{}{}}
+		require.NoError(t, planTable(plan, mStruct, schema))
+		require.Len(t, plan.Statements, 1)
+		assert.False(t, plan.IsDestructive())
+
+		planConstraints(plan, mStruct, schema)
+		expected := []string{
+			`ALTER TABLE "public"."basic_models" ADD PRIMARY KEY (id);`,
+			`ALTER TABLE "public"."basic_models" ADD CONSTRAINT unique_basic_models_string UNIQUE (string);`,
+			`ALTER TABLE "public"."basic_models" ALTER COLUMN int SET NOT NULL;`,
+		}
+		require.Len(t, plan.Statements, 4)
+		for i, statement := range plan.Statements[1:] {
+			assert.Equal(t, expected[i], statement.SQL)
+		}
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		plan := &Plan{}
+		schema := &tableSchema{
+			exists: true,
+			columns: []*columnSchema{
+				{name: "id", dataType: "integer", notNull: true},
+				{name: "string", dataType: "text"},
+				{name: "timed", dataType: "timestamp without time zone"},
+				{name: "ptr_time", dataType: "timestamp without time zone"},
+				{name: "int", dataType: "integer", notNull: true},
+				{name: "int_16", dataType: "integer"},
+				{name: "varchar_20", dataType: "character varying(20)", notNull: true},
+				{name: "float_32", dataType: "real"},
+				{name: "int_array", dataType: "integer[]"},
+				{name: "removed", dataType: "text"},
+			},
+			constraints: map[string]*constraintSchema{
+				"basic_models_pkey":          {name: "basic_models_pkey", kind: constraintPrimary, columns: []string{"id"}},
+				"unique_basic_models_string": {name: "unique_basic_models_string", kind: constraintUnique, columns: []string{"string"}},
+				"unique_basic_models_int":    {name: "unique_basic_models_int", kind: constraintUnique, columns: []string{"int"}},
+			},
+			indexes: map[string]struct{}{"nrn_auto_stale": {}},
+		}
+		require.NoError(t, planTable(plan, mStruct, schema))
+		planConstraints(plan, mStruct, schema)
+		planIndexes(plan, mStruct, schema)
+
+		expected := []*Statement{
+			{SQL: `ALTER TABLE "public"."basic_models" ALTER COLUMN "int_16" TYPE smallint USING "int_16"::smallint;`, Destructive: true},
+			{SQL: `ALTER TABLE "public".basic_models ADD int_slice integer[];`},
+			{SQL: `ALTER TABLE "public"."basic_models" DROP COLUMN "removed";`, Destructive: true},
+			{SQL: `ALTER TABLE "public"."basic_models" DROP CONSTRAINT "unique_basic_models_int";`},
+			{SQL: `ALTER TABLE "public"."basic_models" ALTER varchar_20 DROP NOT NULL;`},
+			{SQL: `DROP INDEX "public"."nrn_auto_stale";`},
+		}
+		assert.Equal(t, expected, plan.Statements)
+		assert.True(t, plan.IsDestructive())
+
+		err := plan.Apply(context.Background(), nil, false)
+		assert.True(t, errors.Is(err, ErrDestructiveMigration))
+	})
+}
+
+// TestIsWideningType tests the detection of the column type changes that keep the values.
+func TestIsWideningType(t *testing.T) {
+	assert.True(t, isWideningType("smallint", "integer"))
+	assert.True(t, isWideningType("integer", "bigint"))
+	assert.True(t, isWideningType("character varying", "text"))
+	assert.False(t, isWideningType("integer", "smallint"))
+	assert.False(t, isWideningType("text", "integer"))
+	assert.False(t, isWideningType("bigint", "double precision"))
+}
+
+// TestPlanForeignKeys tests the planning of the relationship foreign keys.
+func TestPlanForeignKeys(t *testing.T) {
+	m := tCtrl(t, &tests.Blog{}, &tests.Post{}, &tests.Comment{})
+
+	blog, ok := m.GetModelStruct(&tests.Blog{})
+	require.True(t, ok)
+	post, ok := m.GetModelStruct(&tests.Post{})
+	require.True(t, ok)
+	comment, ok := m.GetModelStruct(&tests.Comment{})
+	require.True(t, ok)
+
+	foreignKeys := modelsForeignKeys(comment, blog, post)
+
+	t.Run("Order", func(t *testing.T) {
+		// The blogs and posts reference each other, thus the first of these in the input references the other.
+		assert.Equal(t, []*mapping.ModelStruct{blog, post, comment}, orderModels(foreignKeys, post, blog, comment))
+		assert.Equal(t, []*mapping.ModelStruct{post, blog, comment}, orderModels(foreignKeys, blog, post, comment))
+		assert.Equal(t, []*mapping.ModelStruct{post, comment}, orderModels(foreignKeys, comment, post))
+	})
+
+	t.Run("Actions", func(t *testing.T) {
+		require.Len(t, foreignKeys[comment], 1)
+		assert.Equal(t, `ALTER TABLE "public"."comments" ADD CONSTRAINT "fk_comments_post_id" FOREIGN KEY (post_id) REFERENCES "public"."posts" (id) ON DELETE CASCADE ON UPDATE NO ACTION;`,
+			foreignKeys[comment][0].definition())
+
+		currentPost, ok := blog.RelationByName("CurrentPost")
+		require.True(t, ok)
+		definition, err := CForeignKey.SQLName(currentPost)
+		require.NoError(t, err)
+		assert.Equal(t, `ALTER TABLE "public"."blogs" ADD CONSTRAINT "fk_blogs_current_post_id" FOREIGN KEY (current_post_id) REFERENCES "public"."posts" (id) ON DELETE NO ACTION ON UPDATE NO ACTION;`, definition)
+
+		_, err = CForeignKey.SQLName(blog.MustFieldByName("Title"))
+		assert.True(t, errors.Is(err, mapping.ErrMapping))
+	})
+
+	t.Run("Changed", func(t *testing.T) {
+		plan := &Plan{}
+		schema := &tableSchema{constraints: map[string]*constraintSchema{
+			"fk_comments_post_id": {name: "fk_comments_post_id", kind: constraintForeign, columns: []string{"post_id"},
+				refSchema: "public", refTable: "posts", refColumns: []string{"id"}, onDelete: "a", onUpdate: "a"},
+		}}
+		planForeignKeys(plan, comment, schema, foreignKeys[comment])
+		expected := []*Statement{
+			{SQL: `ALTER TABLE "public"."comments" DROP CONSTRAINT "fk_comments_post_id";`},
+			{SQL: `ALTER TABLE "public"."comments" ADD CONSTRAINT "fk_comments_post_id" FOREIGN KEY (post_id) REFERENCES "public"."posts" (id) ON DELETE CASCADE ON UPDATE NO ACTION NOT VALID;`},
+			{SQL: `ALTER TABLE "public"."comments" VALIDATE CONSTRAINT "fk_comments_post_id";`},
+		}
+		assert.Equal(t, expected, plan.Statements)
+
+		// The existing foreign key that was not validated is only validated.
+		plan = &Plan{}
+		schema.constraints["fk_comments_post_id"].onDelete = "c"
+		planForeignKeys(plan, comment, schema, foreignKeys[comment])
+		assert.Equal(t, []*Statement{{SQL: `ALTER TABLE "public"."comments" VALIDATE CONSTRAINT "fk_comments_post_id";`}}, plan.Statements)
+
+		plan = &Plan{}
+		schema.constraints["fk_comments_post_id"].validated = true
+		planForeignKeys(plan, comment, schema, foreignKeys[comment])
+		assert.True(t, plan.IsEmpty())
+	})
+}
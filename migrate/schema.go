@@ -0,0 +1,251 @@
+package migrate
+
+import (
+	"context"
+	"strings"
+
+	"github.com/neuronlabs/neuron/mapping"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+// Postgres constraint types as stored in the pg_constraint catalog.
+const (
+	constraintPrimary = "p"
+	constraintUnique  = "u"
+	constraintForeign = "f"
+)
+
+// tableSchema is the schema of the existing table read from the database catalogs.
+type tableSchema struct {
+	exists bool
+	// columns are the table columns in their definition order.
+	columns []*columnSchema
+	// constraints are the table constraints by their names.
+	constraints map[string]*constraintSchema
+	// indexes are the names of the table indexes.
+	indexes map[string]struct{}
+}
+
+// column gets the table column with given 'name'.
+func (t *tableSchema) column(name string) (*columnSchema, bool) {
+	for _, column := range t.columns {
+		if column.name == name {
+			return column, true
+		}
+	}
+	return nil, false
+}
+
+// constraintsOf gets the table constraints of given 'kind'.
+func (t *tableSchema) constraintsOf(kind string) (constraints []*constraintSchema) {
+	for _, constraint := range t.constraints {
+		if constraint.kind == kind {
+			constraints = append(constraints, constraint)
+		}
+	}
+	return constraints
+}
+
+// columnSchema is the schema of the existing table column.
+type columnSchema struct {
+	name     string
+	dataType string
+	notNull  bool
+}
+
+// constraintSchema is the schema of the existing table constraint.
+type constraintSchema struct {
+	name    string
+	kind    string
+	columns []string
+	// The foreign key constraints references.
+	refSchema, refTable string
+	refColumns          []string
+	onDelete, onUpdate  string
+	// validated is false for the constraints that were not checked against the existing rows.
+	validated bool
+}
+
+// readTableSchema reads the schema of the model's table. If the table doesn't exist the result is not marked
+// as existing.
+func readTableSchema(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct) (*tableSchema, error) {
+	schema := &tableSchema{constraints: map[string]*constraintSchema{}, indexes: map[string]struct{}{}}
+	exists, err := existsTable(ctx, conn, model)
+	if err != nil || !exists {
+		return schema, err
+	}
+	schema.exists = true
+
+	if err = readColumns(ctx, conn, model, schema); err != nil {
+		return nil, err
+	}
+	if err = readConstraints(ctx, conn, model, schema); err != nil {
+		return nil, err
+	}
+	if err = readIndexes(ctx, conn, model, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+func readColumns(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, schema *tableSchema) error {
+	rows, err := conn.Query(ctx, `SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`, model.DatabaseSchemaName, model.DatabaseName)
+	if err != nil {
+		log.Debugf("Querying columns of the table: '%s' failed: %v", model.DatabaseName, err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		column := &columnSchema{}
+		if err = rows.Scan(&column.name, &column.dataType, &column.notNull); err != nil {
+			return err
+		}
+		schema.columns = append(schema.columns, column)
+	}
+	return rows.Err()
+}
+
+func readConstraints(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, schema *tableSchema) error {
+	rows, err := conn.Query(ctx, `SELECT con.conname, con.contype::text,
+	ARRAY(SELECT a.attname::text FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum ORDER BY k.ord),
+	COALESCE(rn.nspname::text, ''), COALESCE(rc.relname::text, ''),
+	ARRAY(SELECT a.attname::text FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum ORDER BY k.ord),
+	con.confdeltype::text, con.confupdtype::text, con.convalidated
+FROM pg_constraint con
+JOIN pg_class c ON c.oid = con.conrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_class rc ON rc.oid = con.confrelid
+LEFT JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2`, model.DatabaseSchemaName, model.DatabaseName)
+	if err != nil {
+		log.Debugf("Querying constraints of the table: '%s' failed: %v", model.DatabaseName, err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		constraint := &constraintSchema{}
+		if err = rows.Scan(&constraint.name, &constraint.kind, &constraint.columns, &constraint.refSchema,
+			&constraint.refTable, &constraint.refColumns, &constraint.onDelete, &constraint.onUpdate, &constraint.validated); err != nil {
+			return err
+		}
+		constraint.onDelete = strings.TrimSpace(constraint.onDelete)
+		constraint.onUpdate = strings.TrimSpace(constraint.onUpdate)
+		schema.constraints[constraint.name] = constraint
+	}
+	return rows.Err()
+}
+
+func readIndexes(ctx context.Context, conn internal.Connection, model *mapping.ModelStruct, schema *tableSchema) error {
+	rows, err := conn.Query(ctx, "SELECT indexname FROM pg_indexes WHERE schemaname = $1 AND tablename = $2", model.DatabaseSchemaName, model.DatabaseName)
+	if err != nil {
+		log.Debugf("Querying indexes of the table: '%s' failed: %v", model.DatabaseName, err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return err
+		}
+		schema.indexes[name] = struct{}{}
+	}
+	return rows.Err()
+}
+
+// typeAliases are the postgres canonical names of the data type aliases.
+var typeAliases = map[string]string{
+	"serial":      "integer",
+	"serial4":     "integer",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"int":         "integer",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"int2":        "smallint",
+	"float4":      "real",
+	"float8":      "double precision",
+	"double":      "double precision",
+	"bool":        "boolean",
+	"decimal":     "numeric",
+	"varchar":     "character varying",
+	"char":        "character",
+	"bpchar":      "character",
+}
+
+// normalizeType gets the data type 'name' in the form returned by the postgres format_type function, so that
+// the model's column data types could be compared with the existing ones.
+func normalizeType(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	var array string
+	if i := strings.IndexRune(name, '['); i != -1 {
+		// The postgres doesn't enforce the array dimensions.
+		name, array = strings.TrimSpace(name[:i]), "[]"
+	}
+	var params, suffix string
+	if i := strings.IndexRune(name, '('); i != -1 {
+		if j := strings.IndexRune(name[i:], ')'); j != -1 {
+			params = strings.Replace(name[i:i+j+1], " ", "", -1)
+			suffix = strings.TrimSpace(name[i+j+1:])
+			name = strings.TrimSpace(name[:i])
+		}
+	}
+	if params == "()" {
+		params = ""
+	}
+	for _, base := range []string{"timestamp", "time"} {
+		if strings.HasPrefix(name, base+" ") {
+			name, suffix = base, strings.TrimSpace(name[len(base):])
+			break
+		}
+	}
+	switch name {
+	case "timestamp", "time":
+		if suffix == "" {
+			suffix = "without time zone"
+		}
+	case "timestamptz":
+		name, suffix = "timestamp", "with time zone"
+	case "timetz":
+		name, suffix = "time", "with time zone"
+	default:
+		if alias, ok := typeAliases[name]; ok {
+			name = alias
+		}
+	}
+	name += params
+	if suffix != "" {
+		name += " " + suffix
+	}
+	return name + array
+}
+
+// wideningTypes are the normalized data types that the column could be changed to without losing its values.
+var wideningTypes = map[string][]string{
+	"smallint":          {"integer", "bigint", "numeric", "real", "double precision"},
+	"integer":           {"bigint", "numeric", "double precision"},
+	"bigint":            {"numeric"},
+	"real":              {"double precision"},
+	"character varying": {"text"},
+}
+
+// isWideningType checks if changing the column normalized data type 'from' to 'to' keeps all the column values.
+// The narrowing, lossy and unknown type changes are destructive.
+func isWideningType(from, to string) bool {
+	for _, widening := range wideningTypes[from] {
+		if widening == to {
+			return true
+		}
+	}
+	return false
+}
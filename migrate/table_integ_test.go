@@ -8,12 +8,15 @@ import (
 	"testing"
 
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
 )
 
 var cfg pgxpool.Config
@@ -46,3 +49,120 @@ func TestAutoMigrateModels(t *testing.T) {
 		}
 	}
 }
+
+// TestPlanMigratedModels tests that the plan of the migrated models is empty.
+func TestPlanMigratedModels(t *testing.T) {
+	repoCfg := internal.TestingPostgresConfig(t)
+	models := []mapping.Model{&Model{}, &BasicModel{}}
+
+	m := tCtrl(t, models...)
+
+	ctx := context.Background()
+	db, err := pgxpool.ConnectConfig(ctx, repoCfg)
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	defer func() {
+		for _, modelStruct := range m.Models() {
+			_, err = db.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s.%s;", quoteIdentifier(modelStruct.DatabaseSchemaName), modelStruct.DatabaseName))
+			if err != nil {
+				log.Debugf("Error while dropping table: %v", err)
+			}
+		}
+	}()
+
+	plan, err := PlanModels(ctx, db, m.Models()...)
+	require.NoError(t, err)
+	require.False(t, plan.IsEmpty())
+	require.False(t, plan.IsDestructive())
+	require.NoError(t, plan.Apply(ctx, db, false))
+
+	plan, err = PlanModels(ctx, db, m.Models()...)
+	require.NoError(t, err)
+	require.True(t, plan.IsEmpty(), plan.String())
+}
+
+// TestMigrateColumnTypes tests the migration of the changed column types and the stale columns.
+func TestMigrateColumnTypes(t *testing.T) {
+	repoCfg := internal.TestingPostgresConfig(t)
+	m := tCtrl(t, &Model{})
+
+	ctx := context.Background()
+	db, err := pgxpool.ConnectConfig(ctx, repoCfg)
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	modelStruct, ok := m.GetModelStruct(&Model{})
+	require.True(t, ok)
+	defer func() {
+		_, err = db.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s.%s;", quoteIdentifier(modelStruct.DatabaseSchemaName), modelStruct.DatabaseName))
+		if err != nil {
+			log.Debugf("Error while dropping table: %v", err)
+		}
+	}()
+
+	require.NoError(t, Models(ctx, db, modelStruct))
+	_, err = db.Exec(ctx, "ALTER TABLE public.models ALTER COLUMN attribute TYPE varchar(10); ALTER TABLE public.models ADD legacy text;")
+	require.NoError(t, err)
+
+	err = ModelsWithOptions(ctx, db, Options{StaleColumns: StaleColumnsRename}, modelStruct)
+	require.True(t, errors.Is(err, ErrDestructiveMigration))
+
+	require.NoError(t, ModelsWithOptions(ctx, db, Options{AllowDestructive: true, StaleColumns: StaleColumnsRename}, modelStruct))
+
+	schema := &tableSchema{}
+	require.NoError(t, readColumns(ctx, db, modelStruct, schema))
+	column, ok := schema.column("attribute")
+	require.True(t, ok)
+	assert.Equal(t, "text", column.dataType)
+
+	_, ok = schema.column("legacy")
+	assert.False(t, ok)
+	_, ok = schema.column("nrn_stale_legacy")
+	assert.True(t, ok)
+}
+
+// TestMigrateForeignKeys tests the migration of the relationship foreign keys.
+func TestMigrateForeignKeys(t *testing.T) {
+	repoCfg := internal.TestingPostgresConfig(t)
+	m := tCtrl(t, &tests.Blog{}, &tests.Post{}, &tests.Comment{})
+	blog, ok := m.GetModelStruct(&tests.Blog{})
+	require.True(t, ok)
+	post, ok := m.GetModelStruct(&tests.Post{})
+	require.True(t, ok)
+	comment, ok := m.GetModelStruct(&tests.Comment{})
+	require.True(t, ok)
+
+	ctx := context.Background()
+	db, err := pgxpool.ConnectConfig(ctx, repoCfg)
+	require.NoError(t, err)
+	defer db.Close()
+
+	defer func() {
+		_, err = db.Exec(ctx, "DROP TABLE IF EXISTS public.comments, public.posts, public.blogs CASCADE;")
+		if err != nil {
+			log.Debugf("Error while dropping tables: %v", err)
+		}
+	}()
+	require.NoError(t, Models(ctx, db, comment, post, blog))
+
+	for model, field := range map[*mapping.ModelStruct]string{comment: "PostID", post: "BlogID", blog: "CurrentPostID"} {
+		exists, err := existsForeignKey(ctx, db, model, model.MustFieldByName(field))
+		require.NoError(t, err)
+		assert.True(t, exists, model.String())
+	}
+
+	plan, err := PlanModels(ctx, db, comment, post, blog)
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty(), plan.String())
+
+	// The foreign key of the table with the rows referencing not existing rows could not be created.
+	_, err = db.Exec(ctx, `ALTER TABLE public.comments DROP CONSTRAINT fk_comments_post_id;
+INSERT INTO public.comments (post_id, body) VALUES (1000, '');`)
+	require.NoError(t, err)
+	err = Models(ctx, db, comment, post, blog)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrForeignKeyViolation))
+}
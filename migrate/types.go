@@ -341,6 +341,19 @@ func (a *ArrayDataType) GetName() string {
 	return a.Subtype.GetName() + "[" + strconv.Itoa(a.Len) + "]"
 }
 
+// ColumnType gets the postgres column data type name of the 'field' i.e.: 'integer' for the serial primary key.
+// The data types defined by the external functions have no column type.
+func ColumnType(field *mapping.StructField) (string, error) {
+	dt, err := findDataType(field)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := dt.(ExternalDataTyper); ok {
+		return "", errors.WrapDetf(mapping.ErrMapping, "model: '%s' field: '%s' data type is defined by the external function", field.ModelStruct(), field)
+	}
+	return normalizeType(dt.GetName()), nil
+}
+
 // RegisterDataType registers the provided datatype assigning it next id.
 func RegisterDataType(dt DataTyper) error {
 	return registerDataType(dt)
@@ -0,0 +1,61 @@
+package migrate
+
+import (
+	"reflect"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+)
+
+// VersionTag is the database field tag that marks the model's optimistic locking version field.
+// The first database tag is always the column name, thus the field should be tagged as: db:"_;version".
+const VersionTag = "version"
+
+// VersionField gets the model's optimistic locking version field.
+func VersionField(model *mapping.ModelStruct) (*mapping.StructField, bool) {
+	for _, field := range model.Fields() {
+		if IsVersionField(field) {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// IsVersionField checks if the 'field' is tagged as the optimistic locking version field.
+func IsVersionField(field *mapping.StructField) bool {
+	if field.DatabaseSkip() {
+		return false
+	}
+	for _, tag := range field.DatabaseUnknownTags {
+		if tag.Key == VersionTag {
+			return true
+		}
+	}
+	return false
+}
+
+// versionDefault is the column default of the version field.
+const versionDefault = " DEFAULT 0"
+
+func checkVersionField(model *mapping.ModelStruct) error {
+	var version *mapping.StructField
+	for _, field := range model.Fields() {
+		if !IsVersionField(field) {
+			continue
+		}
+		if version != nil {
+			return errors.WrapDetf(mapping.ErrMapping, "model: '%s' has more than one version field", model)
+		}
+		version = field
+		if field.Kind() == mapping.KindPrimary {
+			return errors.WrapDetf(mapping.ErrMapping, "model: '%s' primary key cannot be the version field", model)
+		}
+		switch field.ReflectField().Type.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		default:
+			return errors.WrapDetf(mapping.ErrMapping, "model: '%s' version field: '%s' must be an integer", model, field)
+		}
+	}
+	return nil
+}
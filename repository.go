@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
@@ -25,6 +26,8 @@ var (
 	_ repository.Repository = &Postgres{}
 	// compile time check for the service.Migrator interface.
 	_ repository.Migrator = &Postgres{}
+	// compile time check for the repository.Upserter interface.
+	_ repository.Upserter = &Postgres{}
 )
 
 // Postgres is the neuron repository that allows to query postgres databases.
@@ -43,6 +46,48 @@ type Postgres struct {
 	ConnConfig *pgxpool.Config
 	// SelectNotNullsOnInsert is an option that requires the repository to select the not null fields on insert.
 	SelectNotNullsOnInsert bool
+	// InsertDefaults is an option that makes the insert queries set the zero primary keys to their column defaults,
+	// and omit the not null fields not selected for the insert, so that the defaults, sequences and triggers could
+	// fill them. The selected fields are always inserted with their values, thus in order to use a column default
+	// the field must not be selected - the neuron selects only the non zero fields if no fieldset is provided.
+	// All the model's columns are then scanned back into the inserted models. When set, the SelectNotNullsOnInsert
+	// option is ignored.
+	InsertDefaults bool
+	// CopyThreshold is the number of models above which the insert with the common fieldset uses the COPY protocol.
+	// The primary keys of the copied models are generated or taken from the sequence. Zero (default) disables the COPY.
+	CopyThreshold int
+	// BulkUpdateThreshold is the number of models with the same fieldset above which these are updated with a single
+	// 'UPDATE ... FROM unnest(...)' statement instead of a batch of the per model statements. The column values are
+	// sent as typed arrays, thus the models with the array or externally typed fields are always updated in a batch.
+	// Zero disables the single statement updates.
+	BulkUpdateThreshold int
+	// StrictFilters is an option that requires the repository to return an error for the unsupported filter types
+	// in the update and delete queries. The filters that would be omitted, and thus widen the query, also results
+	// in an error unless the scope has the AllowFullTable option set.
+	StrictFilters bool
+	// TxRetry defines how the RunInTransaction function retries the transactions that failed on the serialization
+	// failure or a deadlock.
+	TxRetry RetryOptions
+	// TxTimeout is the maximum duration of the transaction. The transactions that were neither committed nor rolled
+	// back within this duration are rolled back by the repository and their further use fails with
+	// the query.ErrTxDone error. Zero value disables the timeout.
+	TxTimeout time.Duration
+	// TxReaperInterval is the interval of checking for the expired transactions, so that these are rolled back and
+	// their connections are released even if not used anymore. The checks are done only if the TxTimeout is set.
+	// Zero value disables the checks, thus the expired transactions are rolled back on their next use.
+	TxReaperInterval time.Duration
+	// PreparedGIDPrefix is the prefix of the global identifiers of the transactions prepared by the replicas of this
+	// repository. Only the prepared transactions with this prefix could be marked as orphaned. Empty prefix disables
+	// the orphaned transactions detection.
+	PreparedGIDPrefix string
+	// OrphanedPreparedAge is the minimum age of the prepared transaction, not known to this repository instance,
+	// to be marked as orphaned. It should be longer than the time needed by the coordinator to resolve it.
+	OrphanedPreparedAge time.Duration
+	// MigrateOptions are the options of the MigrateModels method, i.e. what to do with the stale columns.
+	MigrateOptions migrate.Options
+	// Clock is the optional time source for the automatically set CreatedAt and UpdatedAt fields.
+	// If it is not set, the timestamps are set by the postgres server using the now() function.
+	Clock func() time.Time
 
 	// id is the unique identification number of given repository instance.
 	id uuid.UUID
@@ -51,7 +96,15 @@ type Postgres struct {
 	// keywords are the keywords reserved by the current postgres version.
 	keywords map[string]migrate.KeyWordType
 	// transactions is the storage for the transactions for given postgres repository.
-	transactions map[uuid.UUID]pgx.Tx
+	transactions map[uuid.UUID]*transaction
+	// timedOut are the times when the transactions that exceeded their deadline were rolled back by the repository.
+	timedOut map[uuid.UUID]time.Time
+	// prepared are the transaction IDs of the transactions prepared for the two-phase commit by their global identifiers.
+	prepared map[string]uuid.UUID
+	// reaperDone is closed to stop the expired transactions reaper.
+	reaperDone chan struct{}
+	// models are the model structures registered within given repository.
+	models map[*mapping.ModelStruct]struct{}
 	// lock is a transaction locker.
 	lock sync.RWMutex
 }
@@ -70,8 +123,17 @@ func newPostgres() *Postgres {
 	return &Postgres{
 		id:                     uuid.New(),
 		SelectNotNullsOnInsert: true,
+		StrictFilters:          true,
+		CopyThreshold:          DefaultCopyThreshold,
+		BulkUpdateThreshold:    DefaultBulkUpdateThreshold,
+		TxRetry:                DefaultRetryOptions,
+		TxReaperInterval:       DefaultTxReaperInterval,
+		OrphanedPreparedAge:    DefaultOrphanedPreparedAge,
 		keywords:               map[string]migrate.KeyWordType{},
-		transactions:           map[uuid.UUID]pgx.Tx{},
+		transactions:           map[uuid.UUID]*transaction{},
+		timedOut:               map[uuid.UUID]time.Time{},
+		prepared:               map[string]uuid.UUID{},
+		models:                 map[*mapping.ModelStruct]struct{}{},
 		Options:                &repository.Options{},
 	}
 }
@@ -81,9 +143,14 @@ func (p *Postgres) ID() string {
 	return p.id.String()
 }
 
-// Close closes given repository connections.
+// Close closes given repository connections. All the outstanding transactions are rolled back before
+// the connection pool is closed.
 func (p *Postgres) Close(ctx context.Context) (err error) {
-	p.ConnPool.Close()
+	p.stopReaper()
+	p.rollbackAll()
+	if p.ConnPool != nil {
+		p.ConnPool.Close()
+	}
 	return nil
 }
 
@@ -112,6 +179,7 @@ func (p *Postgres) Dial(ctx context.Context) (err error) {
 		log.Errorf("Getting keywords for the postgres version: '%d' failed: %v", p.postgresVersion, err)
 		return err
 	}
+	p.startReaper()
 	return nil
 }
 
@@ -127,9 +195,40 @@ func (p *Postgres) MigrateModels(ctx context.Context, models ...*mapping.ModelSt
 	if p.ConnPool == nil {
 		return errors.Wrapf(repository.ErrConnection, "no connection established")
 	}
-	if err := migrate.Models(ctx, p.ConnPool, models...); err != nil {
+	if err := migrate.ModelsWithOptions(ctx, p.ConnPool, p.MigrateOptions, models...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PlanMigration compares the database schema with the 'models' definitions and gets the migration plan without
+// applying it. The plan could be reviewed and then applied using the ApplyMigration method.
+func (p *Postgres) PlanMigration(ctx context.Context, models ...*mapping.ModelStruct) (*migrate.Plan, error) {
+	if p.ConnPool == nil {
+		return nil, errors.Wrapf(repository.ErrConnection, "no connection established")
+	}
+	return migrate.PlanModels(ctx, p.ConnPool, models...)
+}
+
+// ApplyMigration applies the migration 'plan' within a single transaction. The plans with the destructive
+// statements are applied only if these are allowed.
+func (p *Postgres) ApplyMigration(ctx context.Context, plan *migrate.Plan, allowDestructive bool) error {
+	if p.ConnPool == nil {
+		return errors.Wrapf(repository.ErrConnection, "no connection established")
+	}
+	tx, err := p.ConnPool.Begin(ctx)
+	if err != nil {
+		return errors.WrapDetf(p.neuronError(err), "begin migration transaction failed: %v", err)
+	}
+	if err = plan.Apply(ctx, tx, allowDestructive); err != nil {
+		if er := tx.Rollback(ctx); er != nil {
+			log.Errorf("Rolling back migration failed: %v", er)
+		}
 		return err
 	}
+	if err = tx.Commit(ctx); err != nil {
+		return errors.WrapDetf(p.neuronError(err), "commit migration failed: %v", err)
+	}
 	return nil
 }
 
@@ -164,7 +263,15 @@ func (p *Postgres) HealthCheck(ctx context.Context) (*repository.HealthResponse,
 
 // RegisterModels implements repository.Repository interface.
 func (p *Postgres) RegisterModels(models ...*mapping.ModelStruct) error {
-	return migrate.PrepareModels(models...)
+	if err := migrate.PrepareModels(models...); err != nil {
+		return err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, model := range models {
+		p.models[model] = struct{}{}
+	}
+	return nil
 }
 
 /**
@@ -190,6 +297,9 @@ func (p *Postgres) establishConnection(ctx context.Context) (err error) {
 }
 
 func (p *Postgres) neuronError(err error) error {
+	if err == pgx.ErrTxClosed {
+		return query.ErrTxDone
+	}
 	mapped, ok := Get(err)
 	if ok {
 		return mapped
@@ -199,30 +309,40 @@ func (p *Postgres) neuronError(err error) error {
 
 func (p *Postgres) connection(s *query.Scope) internal.Connection {
 	if tx := s.Transaction; tx != nil {
-		return p.getTransaction(tx.ID)
+		t, timedOut := p.activeTransaction(tx.ID)
+		if timedOut {
+			// The timed out transaction is rolled back, thus its queries fail with the pgx.ErrTxClosed.
+			return timedOutConnection{}
+		}
+		if t == nil {
+			return nil
+		}
+		return t.tx
 	}
 	return p.ConnPool
 }
 
-func (p *Postgres) getTransaction(id uuid.UUID) pgx.Tx {
+func (p *Postgres) isModelRegistered(model *mapping.ModelStruct) bool {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
-	return p.transactions[id]
+	_, ok := p.models[model]
+	return ok
 }
 
 func (p *Postgres) clearTransaction(id uuid.UUID) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	delete(p.transactions, id)
+	delete(p.timedOut, id)
 }
 
-func (p *Postgres) setTransaction(id uuid.UUID, tx pgx.Tx) {
+func (p *Postgres) setTransaction(id uuid.UUID, t *transaction) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
-	p.transactions[id] = tx
+	p.transactions[id] = t
 }
 
-func (p *Postgres) checkTransaction(id uuid.UUID) (pgx.Tx, bool) {
+func (p *Postgres) checkTransaction(id uuid.UUID) (*transaction, bool) {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
 	tx, ok := p.transactions[id]
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+)
+
+// AllowFullTable sets the scope option that allows the update and delete queries to affect the whole table,
+// even if all of the scope's filters were omitted (i.e. all filtered fields are marked with db:"-").
+func AllowFullTable(s *query.Scope) {
+	s.StoreSet(internal.FullTableKey, true)
+}
+
+func isFullTableAllowed(s *query.Scope) bool {
+	return isOptionSet(s, internal.FullTableKey)
+}
+
+// IncludeDeleted sets the scope option that disables the automatic soft delete filter for the models
+// with the DeletedAt field. The find, count and update queries would then also match soft deleted models.
+// The updates of the DeletedAt field, i.e. restoring the soft deleted models, match these without this option.
+func IncludeDeleted(s *query.Scope) {
+	s.StoreSet(internal.IncludeDeletedKey, true)
+}
+
+func isIncludeDeleted(s *query.Scope) bool {
+	return isOptionSet(s, internal.IncludeDeletedKey)
+}
+
+// HardDelete sets the scope option that deletes the models with the DeletedAt field permanently,
+// instead of setting their DeletedAt timestamp.
+func HardDelete(s *query.Scope) {
+	s.StoreSet(internal.HardDeleteKey, true)
+}
+
+func isHardDelete(s *query.Scope) bool {
+	return isOptionSet(s, internal.HardDeleteKey)
+}
+
+// ReturnDeleted sets the scope option that returns the rows removed by the delete query. The deleted rows replace
+// the scope's models. Only the fields from the scope's common field set are returned, or all the model's fields
+// if the scope has no field set.
+func ReturnDeleted(s *query.Scope) {
+	s.StoreSet(internal.ReturnDeletedKey, true)
+}
+
+func isReturnDeleted(s *query.Scope) bool {
+	return isOptionSet(s, internal.ReturnDeletedKey)
+}
+
+// ReturnUpdated sets the scope option that returns the 'fields' of the rows changed by the update query, or all
+// the model's fields if no 'fields' are provided. The values changed by the database i.e. by triggers or defaults,
+// are scanned back into the updated models. The rows changed by the update with filters replace the scope's models.
+func ReturnUpdated(s *query.Scope, fields ...*mapping.StructField) {
+	if len(fields) == 0 {
+		fields = s.ModelStruct.Fields()
+	}
+	s.StoreSet(internal.ReturnUpdatedKey, mapping.FieldSet(fields))
+}
+
+// returnUpdatedFields gets the fields returned by the update query set by the ReturnUpdated option.
+func returnUpdatedFields(s *query.Scope) ([]*mapping.StructField, bool) {
+	v, ok := s.StoreGet(internal.ReturnUpdatedKey)
+	if !ok {
+		return nil, false
+	}
+	fieldSet, _ := v.(mapping.FieldSet)
+	var fields []*mapping.StructField
+	for _, field := range fieldSet {
+		if !field.DatabaseSkip() {
+			fields = append(fields, field)
+		}
+	}
+	return fields, len(fields) > 0
+}
+
+// InsertDefaults sets the scope option that inserts the zero primary keys as their column defaults, omits the fields
+// not selected for the insert, so that these are set by their column defaults, and scans all the model's columns
+// back into the inserted models. It is the per query equivalent of the Postgres.InsertDefaults option.
+func InsertDefaults(s *query.Scope) {
+	s.StoreSet(internal.InsertDefaultsKey, true)
+}
+
+// OnConflict sets the conflict target and the conflict action of the Upsert query.
+func OnConflict(s *query.Scope, conflict Conflict) {
+	s.StoreSet(internal.ConflictKey, conflict)
+}
+
+// UpsertResults gets the results of the Upsert query. The results are ordered just as the scope's models.
+func UpsertResults(s *query.Scope) []UpsertResult {
+	v, ok := s.StoreGet(internal.UpsertResultsKey)
+	if !ok {
+		return nil
+	}
+	results, _ := v.([]UpsertResult)
+	return results
+}
+
+// LockRows sets the row level locking clause of the find query i.e. 'FOR UPDATE SKIP LOCKED'. The rows are locked
+// until the end of the transaction, thus the scope must be in a transaction.
+func LockRows(s *query.Scope, lock RowLock) {
+	s.StoreSet(internal.RowLockKey, lock)
+}
+
+// WithCursor sets the keyset (cursor) pagination for the find query. The 'cursor' is the opaque value obtained
+// from the PageCursors of the previous query. An empty 'cursor' selects the first page. The cursor pagination
+// requires the pagination limit to be set and cannot be used with the offset.
+func WithCursor(s *query.Scope, cursor string) {
+	s.StoreSet(internal.CursorKey, cursor)
+}
+
+// PageCursors gets the next and previous page cursors for the find query with the cursor pagination.
+// An empty cursor means that there is no such page.
+func PageCursors(s *query.Scope) (next, prev string) {
+	v, ok := s.StoreGet(internal.PageCursorsKey)
+	if !ok {
+		return "", ""
+	}
+	cursors, ok := v.(pageCursors)
+	if !ok {
+		return "", ""
+	}
+	return cursors.next, cursors.prev
+}
+
+func isCursorPagination(s *query.Scope) bool {
+	_, ok := s.StoreGet(internal.CursorKey)
+	return ok
+}
+
+func isOptionSet(s *query.Scope, key interface{}) bool {
+	v, ok := s.StoreGet(key)
+	if !ok {
+		return false
+	}
+	set, _ := v.(bool)
+	return set
+}
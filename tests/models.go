@@ -40,3 +40,57 @@ type ArrayModel struct {
 	SliceInt    []int
 	SliceString []string
 }
+
+// Blog is a blog model.
+type Blog struct {
+	ID            int       `neuron:"type=primary"`
+	Title         string    `neuron:"type=attr;name=title"`
+	Posts         []*Post   `neuron:"type=relation;name=posts;foreign=BlogID"`
+	CurrentPost   *Post     `neuron:"type=relation;name=current_post"`
+	CurrentPostID uint64    `neuron:"type=foreign;name=current_post_id"`
+	CreatedAt     time.Time `neuron:"type=attr;name=created_at;flags=iso8601"`
+	ViewCount     int       `neuron:"type=attr;name=view_count;flags=omitempty"`
+}
+
+// Post is a post model.
+type Post struct {
+	ID            uint64     `neuron:"type=primary"`
+	BlogID        int        `neuron:"type=foreign"`
+	Title         string     `neuron:"type=attr;name=title"`
+	Body          string     `neuron:"type=attr;name=body"`
+	Comments      []*Comment `neuron:"type=relation;name=comments;foreign=PostID"`
+	LatestComment *Comment   `neuron:"type=relation;name=latest_comment;foreign=PostID"`
+}
+
+// Comment is a comment model.
+type Comment struct {
+	ID     int    `neuron:"type=primary"`
+	PostID uint64 `neuron:"type=foreign" db:"_;on_delete=cascade"`
+	Body   string `neuron:"type=attr;name=body"`
+}
+
+// ManyToManyModel is the model with many2many relationship.
+type ManyToManyModel struct {
+	ID        int             `neuron:"type=primary"`
+	Many2Many []*RelatedModel `neuron:"type=relation;many2many=JoinModel;foreign=ForeignKey,MtMForeignKey"`
+}
+
+// JoinModel is the model used as a join model for the many2many relationships.
+type JoinModel struct {
+	ID            int `neuron:"type=primary"`
+	ForeignKey    int `neuron:"type=foreign"`
+	MtMForeignKey int `neuron:"type=foreign"`
+}
+
+// RelatedModel is the related model in the many2many relationship.
+type RelatedModel struct {
+	ID         int     `neuron:"type=primary"`
+	FloatField float64 `neuron:"type=attr"`
+}
+
+// VersionedModel is the model with the optimistic locking version field.
+type VersionedModel struct {
+	ID      int    `neuron:"type=primary"`
+	Name    string `neuron:"type=attr"`
+	Version int    `neuron:"type=attr" db:"_;version"`
+}
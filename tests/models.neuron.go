@@ -17,8 +17,14 @@ import (
 // Neuron_Models stores all generated models in this package.
 var Neuron_Models = []mapping.Model{
 	&ArrayModel{},
+	&Blog{},
+	&Comment{},
+	&JoinModel{},
+	&ManyToManyModel{},
 	&Model{},
 	&OmitModel{},
+	&Post{},
+	&RelatedModel{},
 	&SimpleModel{},
 }
 
@@ -296,188 +302,159 @@ func (a *ArrayModel) ParseFieldsStringValue(field *mapping.StructField, value st
 	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: ArrayModel'", field.Name())
 }
 
-// Compile time check if Model implements mapping.Model interface.
-var _ mapping.Model = &Model{}
+// Compile time check if Blog implements mapping.Model interface.
+var _ mapping.Model = &Blog{}
 
 // NeuronCollectionName implements mapping.Model interface method.
-// Returns the name of the collection for the 'Model'.
-func (m *Model) NeuronCollectionName() string {
-	return "models"
+// Returns the name of the collection for the 'Blog'.
+func (b *Blog) NeuronCollectionName() string {
+	return "blogs"
 }
 
 // IsPrimaryKeyZero implements mapping.Model interface method.
-func (m *Model) IsPrimaryKeyZero() bool {
-	return m.ID == 0
+func (b *Blog) IsPrimaryKeyZero() bool {
+	return b.ID == 0
 }
 
 // GetPrimaryKeyValue implements mapping.Model interface method.
-func (m *Model) GetPrimaryKeyValue() interface{} {
-	return m.ID
+func (b *Blog) GetPrimaryKeyValue() interface{} {
+	return b.ID
 }
 
 // GetPrimaryKeyStringValue implements mapping.Model interface method.
-func (m *Model) GetPrimaryKeyStringValue() (string, error) {
-	return strconv.FormatInt(int64(m.ID), 10), nil
+func (b *Blog) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(b.ID), 10), nil
 }
 
 // GetPrimaryKeyAddress implements mapping.Model interface method.
-func (m *Model) GetPrimaryKeyAddress() interface{} {
-	return &m.ID
+func (b *Blog) GetPrimaryKeyAddress() interface{} {
+	return &b.ID
 }
 
 // GetPrimaryKeyHashableValue implements mapping.Model interface method.
-func (m *Model) GetPrimaryKeyHashableValue() interface{} {
-	return m.ID
+func (b *Blog) GetPrimaryKeyHashableValue() interface{} {
+	return b.ID
 }
 
 // GetPrimaryKeyZeroValue implements mapping.Model interface method.
-func (m *Model) GetPrimaryKeyZeroValue() interface{} {
+func (b *Blog) GetPrimaryKeyZeroValue() interface{} {
 	return 0
 }
 
 // SetPrimaryKey implements mapping.Model interface method.
-func (m *Model) SetPrimaryKeyValue(value interface{}) error {
+func (b *Blog) SetPrimaryKeyValue(value interface{}) error {
 	if v, ok := value.(int); ok {
-		m.ID = v
+		b.ID = v
 		return nil
 	}
 	// Check alternate types for given field.
 	switch valueType := value.(type) {
 	case int8:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case int16:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case int32:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case int64:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case uint:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case uint8:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case uint16:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case uint32:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case uint64:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case float32:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	case float64:
-		m.ID = int(valueType)
+		b.ID = int(valueType)
 	default:
-		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'Model'", value)
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'Blog'", value)
 	}
 	return nil
 }
 
 // SetPrimaryKeyStringValue implements mapping.Model interface method.
-func (m *Model) SetPrimaryKeyStringValue(value string) error {
+func (b *Blog) SetPrimaryKeyStringValue(value string) error {
 	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
 	if err != nil {
 		return err
 	}
-	m.ID = int(tmp)
-	return nil
-}
-
-// SetFrom implements FromSetter interface.
-func (m *Model) SetFrom(model mapping.Model) error {
-	if model == nil {
-		return errors.Wrap(query.ErrInvalidInput, "provided nil model to set from")
-	}
-	from, ok := model.(*Model)
-	if !ok {
-		return errors.WrapDetf(mapping.ErrModelNotMatch, "provided model doesn't match the input: %T", model)
-	}
-	*m = *from
+	b.ID = int(tmp)
 	return nil
 }
 
-// Compile time check if Model implements mapping.Fielder interface.
-var _ mapping.Fielder = &Model{}
+// Compile time check if Blog implements mapping.Fielder interface.
+var _ mapping.Fielder = &Blog{}
 
 // GetFieldsAddress gets the address of provided 'field'.
-func (m *Model) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+func (b *Blog) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return &m.ID, nil
-	case 1: // AttrString
-		return &m.AttrString, nil
-	case 2: // StringPtr
-		return &m.StringPtr, nil
-	case 3: // Int
-		return &m.Int, nil
-	case 4: // CreatedAt
-		return &m.CreatedAt, nil
-	case 5: // UpdatedAt
-		return &m.UpdatedAt, nil
-	case 6: // DeletedAt
-		return &m.DeletedAt, nil
+		return &b.ID, nil
+	case 1: // Title
+		return &b.Title, nil
+	case 4: // CurrentPostID
+		return &b.CurrentPostID, nil
+	case 5: // CreatedAt
+		return &b.CreatedAt, nil
+	case 6: // ViewCount
+		return &b.ViewCount, nil
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Model'", field.Name())
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Blog'", field.Name())
 }
 
 // GetFieldZeroValue implements mapping.Fielder interface.s
-func (m *Model) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+func (b *Blog) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
 		return 0, nil
-	case 1: // AttrString
+	case 1: // Title
 		return "", nil
-	case 2: // StringPtr
-		return nil, nil
-	case 3: // Int
+	case 4: // CurrentPostID
 		return 0, nil
-	case 4: // CreatedAt
+	case 5: // CreatedAt
 		return time.Time{}, nil
-	case 5: // UpdatedAt
-		return nil, nil
-	case 6: // DeletedAt
-		return nil, nil
+	case 6: // ViewCount
+		return 0, nil
 	default:
 		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
 	}
 }
 
 // IsFieldZero implements mapping.Fielder interface.
-func (m *Model) IsFieldZero(field *mapping.StructField) (bool, error) {
+func (b *Blog) IsFieldZero(field *mapping.StructField) (bool, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return m.ID == 0, nil
-	case 1: // AttrString
-		return m.AttrString == "", nil
-	case 2: // StringPtr
-		return m.StringPtr == nil, nil
-	case 3: // Int
-		return m.Int == 0, nil
-	case 4: // CreatedAt
-		return m.CreatedAt == time.Time{}, nil
-	case 5: // UpdatedAt
-		return m.UpdatedAt == nil, nil
-	case 6: // DeletedAt
-		return m.DeletedAt == nil, nil
+		return b.ID == 0, nil
+	case 1: // Title
+		return b.Title == "", nil
+	case 4: // CurrentPostID
+		return b.CurrentPostID == 0, nil
+	case 5: // CreatedAt
+		return b.CreatedAt == time.Time{}, nil
+	case 6: // ViewCount
+		return b.ViewCount == 0, nil
 	}
 	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
 }
 
 // SetFieldZeroValue implements mapping.Fielder interface.s
-func (m *Model) SetFieldZeroValue(field *mapping.StructField) error {
+func (b *Blog) SetFieldZeroValue(field *mapping.StructField) error {
 	switch field.Index[0] {
 	case 0: // ID
-		m.ID = 0
-	case 1: // AttrString
-		m.AttrString = ""
-	case 2: // StringPtr
-		m.StringPtr = nil
-	case 3: // Int
-		m.Int = 0
-	case 4: // CreatedAt
-		m.CreatedAt = time.Time{}
-	case 5: // UpdatedAt
-		m.UpdatedAt = nil
-	case 6: // DeletedAt
-		m.DeletedAt = nil
+		b.ID = 0
+	case 1: // Title
+		b.Title = ""
+	case 4: // CurrentPostID
+		b.CurrentPostID = 0
+	case 5: // CreatedAt
+		b.CreatedAt = time.Time{}
+	case 6: // ViewCount
+		b.ViewCount = 0
 	default:
 		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
 	}
@@ -485,395 +462,2321 @@ func (m *Model) SetFieldZeroValue(field *mapping.StructField) error {
 }
 
 // GetHashableFieldValue implements mapping.Fielder interface.
-func (m *Model) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+func (b *Blog) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return m.ID, nil
-	case 1: // AttrString
-		return m.AttrString, nil
-	case 2: // StringPtr
-		if m.StringPtr == nil {
-			return nil, nil
-		}
-		return *m.StringPtr, nil
-	case 3: // Int
-		return m.Int, nil
-	case 4: // CreatedAt
-		return m.CreatedAt, nil
-	case 5: // UpdatedAt
-		if m.UpdatedAt == nil {
-			return nil, nil
-		}
-		return *m.UpdatedAt, nil
-	case 6: // DeletedAt
-		if m.DeletedAt == nil {
-			return nil, nil
-		}
-		return *m.DeletedAt, nil
+		return b.ID, nil
+	case 1: // Title
+		return b.Title, nil
+	case 4: // CurrentPostID
+		return b.CurrentPostID, nil
+	case 5: // CreatedAt
+		return b.CreatedAt, nil
+	case 6: // ViewCount
+		return b.ViewCount, nil
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'Model'", field.Name())
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'Blog'", field.Name())
 }
 
 // GetFieldValue implements mapping.Fielder interface.
-func (m *Model) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+func (b *Blog) GetFieldValue(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return m.ID, nil
-	case 1: // AttrString
-		return m.AttrString, nil
-	case 2: // StringPtr
-		return m.StringPtr, nil
-	case 3: // Int
-		return m.Int, nil
-	case 4: // CreatedAt
-		return m.CreatedAt, nil
-	case 5: // UpdatedAt
-		return m.UpdatedAt, nil
-	case 6: // DeletedAt
-		return m.DeletedAt, nil
+		return b.ID, nil
+	case 1: // Title
+		return b.Title, nil
+	case 4: // CurrentPostID
+		return b.CurrentPostID, nil
+	case 5: // CreatedAt
+		return b.CreatedAt, nil
+	case 6: // ViewCount
+		return b.ViewCount, nil
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Model'", field.Name())
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Blog'", field.Name())
 }
 
 // SetFieldValue implements mapping.Fielder interface.
-func (m *Model) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+func (b *Blog) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
 	switch field.Index[0] {
 	case 0: // ID
 		if v, ok := value.(int); ok {
-			m.ID = v
+			b.ID = v
 			return nil
 		}
 
 		switch v := value.(type) {
 		case int8:
-			m.ID = int(v)
+			b.ID = int(v)
 		case int16:
-			m.ID = int(v)
+			b.ID = int(v)
 		case int32:
-			m.ID = int(v)
+			b.ID = int(v)
 		case int64:
-			m.ID = int(v)
+			b.ID = int(v)
 		case uint:
-			m.ID = int(v)
+			b.ID = int(v)
 		case uint8:
-			m.ID = int(v)
+			b.ID = int(v)
 		case uint16:
-			m.ID = int(v)
+			b.ID = int(v)
 		case uint32:
-			m.ID = int(v)
+			b.ID = int(v)
 		case uint64:
-			m.ID = int(v)
+			b.ID = int(v)
 		case float32:
-			m.ID = int(v)
+			b.ID = int(v)
 		case float64:
-			m.ID = int(v)
+			b.ID = int(v)
 		default:
 			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
 		}
 		return nil
-	case 1: // AttrString
-		if v, ok := value.(string); ok {
-			m.AttrString = v
-			return nil
-		}
-
-		// Check alternate types for the AttrString.
-		if v, ok := value.([]byte); ok {
-			m.AttrString = string(v)
-			return nil
-		}
-		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
-	case 2: // StringPtr
-		if value == nil {
-			m.StringPtr = nil
-			return nil
-		}
-		if v, ok := value.(*string); ok {
-			m.StringPtr = v
-			return nil
-		}
-		// Check if it is non-pointer value.
+	case 1: // Title
 		if v, ok := value.(string); ok {
-			m.StringPtr = &v
+			b.Title = v
 			return nil
 		}
 
-		// Check alternate types for the StringPtr.
+		// Check alternate types for the Title.
 		if v, ok := value.([]byte); ok {
-			temp := string(v)
-			m.StringPtr = &temp
+			b.Title = string(v)
 			return nil
 		}
 		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
-	case 3: // Int
-		if v, ok := value.(int); ok {
-			m.Int = v
+	case 4: // CurrentPostID
+		if v, ok := value.(uint64); ok {
+			b.CurrentPostID = v
 			return nil
 		}
 
 		switch v := value.(type) {
+		case int:
+			b.CurrentPostID = uint64(v)
 		case int8:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case int16:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case int32:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case int64:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case uint:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case uint8:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case uint16:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case uint32:
-			m.Int = int(v)
-		case uint64:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case float32:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		case float64:
-			m.Int = int(v)
+			b.CurrentPostID = uint64(v)
 		default:
 			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
 		}
 		return nil
-	case 4: // CreatedAt
+	case 5: // CreatedAt
 		if v, ok := value.(time.Time); ok {
-			m.CreatedAt = v
+			b.CreatedAt = v
 			return nil
 		}
 
 		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
-	case 5: // UpdatedAt
-		if value == nil {
-			m.UpdatedAt = nil
-			return nil
-		}
-		if v, ok := value.(*time.Time); ok {
-			m.UpdatedAt = v
-			return nil
-		}
-		// Check if it is non-pointer value.
-		if v, ok := value.(time.Time); ok {
-			m.UpdatedAt = &v
+	case 6: // ViewCount
+		if v, ok := value.(int); ok {
+			b.ViewCount = v
 			return nil
 		}
 
-		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
-	case 6: // DeletedAt
-		if value == nil {
-			m.DeletedAt = nil
-			return nil
-		}
-		if v, ok := value.(*time.Time); ok {
-			m.DeletedAt = v
-			return nil
-		}
-		// Check if it is non-pointer value.
-		if v, ok := value.(time.Time); ok {
-			m.DeletedAt = &v
-			return nil
+		switch v := value.(type) {
+		case int8:
+			b.ViewCount = int(v)
+		case int16:
+			b.ViewCount = int(v)
+		case int32:
+			b.ViewCount = int(v)
+		case int64:
+			b.ViewCount = int(v)
+		case uint:
+			b.ViewCount = int(v)
+		case uint8:
+			b.ViewCount = int(v)
+		case uint16:
+			b.ViewCount = int(v)
+		case uint32:
+			b.ViewCount = int(v)
+		case uint64:
+			b.ViewCount = int(v)
+		case float32:
+			b.ViewCount = int(v)
+		case float64:
+			b.ViewCount = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
 		}
-
-		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		return nil
 	default:
-		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'Model'", field.Name())
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'Blog'", field.Name())
 	}
 }
 
 // SetPrimaryKeyStringValue implements mapping.Model interface method.
-func (m *Model) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+func (b *Blog) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
 		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
-	case 1: // AttrString
-		return value, nil
-	case 2: // StringPtr
+	case 1: // Title
 		return value, nil
-	case 3: // Int
-		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
-	case 4: // CreatedAt
-		temp := m.CreatedAt
-		if err := m.CreatedAt.UnmarshalText([]byte(value)); err != nil {
-			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'CreatedAt' value: '%v' to parse string. Err: %v", m.CreatedAt, err)
+	case 4: // CurrentPostID
+		return strconv.ParseUint(value, 10, 64)
+	case 5: // CreatedAt
+		temp := b.CreatedAt
+		if err := b.CreatedAt.UnmarshalText([]byte(value)); err != nil {
+			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'CreatedAt' value: '%v' to parse string. Err: %v", b.CreatedAt, err)
 		}
-		bt, err := m.CreatedAt.MarshalText()
+		bt, err := b.CreatedAt.MarshalText()
 		if err != nil {
-			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'CreatedAt' value: '%v' to parse string. Err: %v", m.CreatedAt, err)
+			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'CreatedAt' value: '%v' to parse string. Err: %v", b.CreatedAt, err)
 		}
-		m.CreatedAt = temp
+		b.CreatedAt = temp
 		return string(bt), nil
-	case 5: // UpdatedAt
-		var base time.Time
+	case 6: // ViewCount
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Blog'", field.Name())
+}
+
+// Compile time check if Blog implements mapping.SingleRelationer interface.
+var _ mapping.SingleRelationer = &Blog{}
+
+// GetRelationModel implements mapping.SingleRelationer interface.
+func (b *Blog) GetRelationModel(relation *mapping.StructField) (mapping.Model, error) {
+	switch relation.Index[0] {
+	case 3: // CurrentPost
+		if b.CurrentPost == nil {
+			return nil, nil
+		}
+		return b.CurrentPost, nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, b)
+	}
+}
+
+// SetRelationModel implements mapping.SingleRelationer interface.
+func (b *Blog) SetRelationModel(relation *mapping.StructField, model mapping.Model) error {
+	switch relation.Index[0] {
+	case 3: // CurrentPost
+		if model == nil {
+			b.CurrentPost = nil
+			return nil
+		} else if currentPost, ok := model.(*Post); ok {
+			b.CurrentPost = currentPost
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid model value: '%T' for relation CurrentPost", model)
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, b)
+	}
+}
+
+// Compile time check for the mapping.MultiRelationer interface implementation.
+var _ mapping.MultiRelationer = &Blog{}
+
+// AddRelationModel implements mapping.MultiRelationer interface.
+func (b *Blog) AddRelationModel(relation *mapping.StructField, model mapping.Model) error {
+	switch relation.Index[0] {
+	case 2: // Posts
+		post, ok := model.(*Post)
+		if !ok {
+			return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid value type: '%T'  for the field: 'Posts'", model)
+		}
+		b.Posts = append(b.Posts, post)
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%T' for the model 'Blog'", model)
+	}
+	return nil
+}
+
+// GetRelationModels implements mapping.MultiRelationer interface.
+func (b *Blog) GetRelationModels(relation *mapping.StructField) (models []mapping.Model, err error) {
+	switch relation.Index[0] {
+	case 2: // Posts
+		for _, model := range b.Posts {
+			models = append(models, model)
+		}
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, b)
+	}
+	return models, nil
+}
+
+// GetRelationModelAt implements mapping.MultiRelationer interface.
+func (b *Blog) GetRelationModelAt(relation *mapping.StructField, index int) (models mapping.Model, err error) {
+	switch relation.Index[0] {
+	case 2: // Posts
+		if index > len(b.Posts)-1 {
+			return nil, errors.Wrapf(mapping.ErrInvalidRelationIndex, "index out of possible range. Model: 'Blog', Field Posts")
+		}
+		return b.Posts[index], nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, b)
+	}
+}
+
+// GetRelationLen implements mapping.MultiRelationer interface.
+func (b *Blog) GetRelationLen(relation *mapping.StructField) (int, error) {
+	switch relation.Index[0] {
+	case 2: // Posts
+		return len(b.Posts), nil
+	default:
+		return 0, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, b)
+	}
+}
+
+// SetRelationModels implements mapping.MultiRelationer interface.
+func (b *Blog) SetRelationModels(relation *mapping.StructField, models ...mapping.Model) error {
+	switch relation.Index[0] {
+	case 2: // Posts
+		temp := make([]*Post, len(models))
+		for i, model := range models {
+			post, ok := model.(*Post)
+			if !ok {
+				return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid value type: '%T'  for the field: 'Posts'", model)
+			}
+			temp[i] = post
+		}
+		b.Posts = temp
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for the model 'Blog'", relation.String())
+	}
+	return nil
+}
+
+// Compile time check if Comment implements mapping.Model interface.
+var _ mapping.Model = &Comment{}
+
+// NeuronCollectionName implements mapping.Model interface method.
+// Returns the name of the collection for the 'Comment'.
+func (c *Comment) NeuronCollectionName() string {
+	return "comments"
+}
+
+// IsPrimaryKeyZero implements mapping.Model interface method.
+func (c *Comment) IsPrimaryKeyZero() bool {
+	return c.ID == 0
+}
+
+// GetPrimaryKeyValue implements mapping.Model interface method.
+func (c *Comment) GetPrimaryKeyValue() interface{} {
+	return c.ID
+}
+
+// GetPrimaryKeyStringValue implements mapping.Model interface method.
+func (c *Comment) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(c.ID), 10), nil
+}
+
+// GetPrimaryKeyAddress implements mapping.Model interface method.
+func (c *Comment) GetPrimaryKeyAddress() interface{} {
+	return &c.ID
+}
+
+// GetPrimaryKeyHashableValue implements mapping.Model interface method.
+func (c *Comment) GetPrimaryKeyHashableValue() interface{} {
+	return c.ID
+}
+
+// GetPrimaryKeyZeroValue implements mapping.Model interface method.
+func (c *Comment) GetPrimaryKeyZeroValue() interface{} {
+	return 0
+}
+
+// SetPrimaryKey implements mapping.Model interface method.
+func (c *Comment) SetPrimaryKeyValue(value interface{}) error {
+	if v, ok := value.(int); ok {
+		c.ID = v
+		return nil
+	}
+	// Check alternate types for given field.
+	switch valueType := value.(type) {
+	case int8:
+		c.ID = int(valueType)
+	case int16:
+		c.ID = int(valueType)
+	case int32:
+		c.ID = int(valueType)
+	case int64:
+		c.ID = int(valueType)
+	case uint:
+		c.ID = int(valueType)
+	case uint8:
+		c.ID = int(valueType)
+	case uint16:
+		c.ID = int(valueType)
+	case uint32:
+		c.ID = int(valueType)
+	case uint64:
+		c.ID = int(valueType)
+	case float32:
+		c.ID = int(valueType)
+	case float64:
+		c.ID = int(valueType)
+	default:
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'Comment'", value)
+	}
+	return nil
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (c *Comment) SetPrimaryKeyStringValue(value string) error {
+	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	if err != nil {
+		return err
+	}
+	c.ID = int(tmp)
+	return nil
+}
+
+// Compile time check if Comment implements mapping.Fielder interface.
+var _ mapping.Fielder = &Comment{}
+
+// GetFieldsAddress gets the address of provided 'field'.
+func (c *Comment) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return &c.ID, nil
+	case 1: // PostID
+		return &c.PostID, nil
+	case 2: // Body
+		return &c.Body, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Comment'", field.Name())
+}
+
+// GetFieldZeroValue implements mapping.Fielder interface.s
+func (c *Comment) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return 0, nil
+	case 1: // PostID
+		return 0, nil
+	case 2: // Body
+		return "", nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+}
+
+// IsFieldZero implements mapping.Fielder interface.
+func (c *Comment) IsFieldZero(field *mapping.StructField) (bool, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return c.ID == 0, nil
+	case 1: // PostID
+		return c.PostID == 0, nil
+	case 2: // Body
+		return c.Body == "", nil
+	}
+	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+}
+
+// SetFieldZeroValue implements mapping.Fielder interface.s
+func (c *Comment) SetFieldZeroValue(field *mapping.StructField) error {
+	switch field.Index[0] {
+	case 0: // ID
+		c.ID = 0
+	case 1: // PostID
+		c.PostID = 0
+	case 2: // Body
+		c.Body = ""
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+	return nil
+}
+
+// GetHashableFieldValue implements mapping.Fielder interface.
+func (c *Comment) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return c.ID, nil
+	case 1: // PostID
+		return c.PostID, nil
+	case 2: // Body
+		return c.Body, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'Comment'", field.Name())
+}
+
+// GetFieldValue implements mapping.Fielder interface.
+func (c *Comment) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return c.ID, nil
+	case 1: // PostID
+		return c.PostID, nil
+	case 2: // Body
+		return c.Body, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Comment'", field.Name())
+}
+
+// SetFieldValue implements mapping.Fielder interface.
+func (c *Comment) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+	switch field.Index[0] {
+	case 0: // ID
+		if v, ok := value.(int); ok {
+			c.ID = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			c.ID = int(v)
+		case int16:
+			c.ID = int(v)
+		case int32:
+			c.ID = int(v)
+		case int64:
+			c.ID = int(v)
+		case uint:
+			c.ID = int(v)
+		case uint8:
+			c.ID = int(v)
+		case uint16:
+			c.ID = int(v)
+		case uint32:
+			c.ID = int(v)
+		case uint64:
+			c.ID = int(v)
+		case float32:
+			c.ID = int(v)
+		case float64:
+			c.ID = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 1: // PostID
+		if v, ok := value.(uint64); ok {
+			c.PostID = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int:
+			c.PostID = uint64(v)
+		case int8:
+			c.PostID = uint64(v)
+		case int16:
+			c.PostID = uint64(v)
+		case int32:
+			c.PostID = uint64(v)
+		case int64:
+			c.PostID = uint64(v)
+		case uint:
+			c.PostID = uint64(v)
+		case uint8:
+			c.PostID = uint64(v)
+		case uint16:
+			c.PostID = uint64(v)
+		case uint32:
+			c.PostID = uint64(v)
+		case float32:
+			c.PostID = uint64(v)
+		case float64:
+			c.PostID = uint64(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 2: // Body
+		if v, ok := value.(string); ok {
+			c.Body = v
+			return nil
+		}
+
+		// Check alternate types for the Body.
+		if v, ok := value.([]byte); ok {
+			c.Body = string(v)
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'Comment'", field.Name())
+	}
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (c *Comment) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 1: // PostID
+		return strconv.ParseUint(value, 10, 64)
+	case 2: // Body
+		return value, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Comment'", field.Name())
+}
+
+// Compile time check if JoinModel implements mapping.Model interface.
+var _ mapping.Model = &JoinModel{}
+
+// NeuronCollectionName implements mapping.Model interface method.
+// Returns the name of the collection for the 'JoinModel'.
+func (j *JoinModel) NeuronCollectionName() string {
+	return "join_models"
+}
+
+// IsPrimaryKeyZero implements mapping.Model interface method.
+func (j *JoinModel) IsPrimaryKeyZero() bool {
+	return j.ID == 0
+}
+
+// GetPrimaryKeyValue implements mapping.Model interface method.
+func (j *JoinModel) GetPrimaryKeyValue() interface{} {
+	return j.ID
+}
+
+// GetPrimaryKeyStringValue implements mapping.Model interface method.
+func (j *JoinModel) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(j.ID), 10), nil
+}
+
+// GetPrimaryKeyAddress implements mapping.Model interface method.
+func (j *JoinModel) GetPrimaryKeyAddress() interface{} {
+	return &j.ID
+}
+
+// GetPrimaryKeyHashableValue implements mapping.Model interface method.
+func (j *JoinModel) GetPrimaryKeyHashableValue() interface{} {
+	return j.ID
+}
+
+// GetPrimaryKeyZeroValue implements mapping.Model interface method.
+func (j *JoinModel) GetPrimaryKeyZeroValue() interface{} {
+	return 0
+}
+
+// SetPrimaryKey implements mapping.Model interface method.
+func (j *JoinModel) SetPrimaryKeyValue(value interface{}) error {
+	if v, ok := value.(int); ok {
+		j.ID = v
+		return nil
+	}
+	// Check alternate types for given field.
+	switch valueType := value.(type) {
+	case int8:
+		j.ID = int(valueType)
+	case int16:
+		j.ID = int(valueType)
+	case int32:
+		j.ID = int(valueType)
+	case int64:
+		j.ID = int(valueType)
+	case uint:
+		j.ID = int(valueType)
+	case uint8:
+		j.ID = int(valueType)
+	case uint16:
+		j.ID = int(valueType)
+	case uint32:
+		j.ID = int(valueType)
+	case uint64:
+		j.ID = int(valueType)
+	case float32:
+		j.ID = int(valueType)
+	case float64:
+		j.ID = int(valueType)
+	default:
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'JoinModel'", value)
+	}
+	return nil
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (j *JoinModel) SetPrimaryKeyStringValue(value string) error {
+	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	if err != nil {
+		return err
+	}
+	j.ID = int(tmp)
+	return nil
+}
+
+// Compile time check if JoinModel implements mapping.Fielder interface.
+var _ mapping.Fielder = &JoinModel{}
+
+// GetFieldsAddress gets the address of provided 'field'.
+func (j *JoinModel) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return &j.ID, nil
+	case 1: // ForeignKey
+		return &j.ForeignKey, nil
+	case 2: // MtMForeignKey
+		return &j.MtMForeignKey, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: JoinModel'", field.Name())
+}
+
+// GetFieldZeroValue implements mapping.Fielder interface.s
+func (j *JoinModel) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return 0, nil
+	case 1: // ForeignKey
+		return 0, nil
+	case 2: // MtMForeignKey
+		return 0, nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+}
+
+// IsFieldZero implements mapping.Fielder interface.
+func (j *JoinModel) IsFieldZero(field *mapping.StructField) (bool, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return j.ID == 0, nil
+	case 1: // ForeignKey
+		return j.ForeignKey == 0, nil
+	case 2: // MtMForeignKey
+		return j.MtMForeignKey == 0, nil
+	}
+	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+}
+
+// SetFieldZeroValue implements mapping.Fielder interface.s
+func (j *JoinModel) SetFieldZeroValue(field *mapping.StructField) error {
+	switch field.Index[0] {
+	case 0: // ID
+		j.ID = 0
+	case 1: // ForeignKey
+		j.ForeignKey = 0
+	case 2: // MtMForeignKey
+		j.MtMForeignKey = 0
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+	return nil
+}
+
+// GetHashableFieldValue implements mapping.Fielder interface.
+func (j *JoinModel) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return j.ID, nil
+	case 1: // ForeignKey
+		return j.ForeignKey, nil
+	case 2: // MtMForeignKey
+		return j.MtMForeignKey, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'JoinModel'", field.Name())
+}
+
+// GetFieldValue implements mapping.Fielder interface.
+func (j *JoinModel) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return j.ID, nil
+	case 1: // ForeignKey
+		return j.ForeignKey, nil
+	case 2: // MtMForeignKey
+		return j.MtMForeignKey, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: JoinModel'", field.Name())
+}
+
+// SetFieldValue implements mapping.Fielder interface.
+func (j *JoinModel) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+	switch field.Index[0] {
+	case 0: // ID
+		if v, ok := value.(int); ok {
+			j.ID = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			j.ID = int(v)
+		case int16:
+			j.ID = int(v)
+		case int32:
+			j.ID = int(v)
+		case int64:
+			j.ID = int(v)
+		case uint:
+			j.ID = int(v)
+		case uint8:
+			j.ID = int(v)
+		case uint16:
+			j.ID = int(v)
+		case uint32:
+			j.ID = int(v)
+		case uint64:
+			j.ID = int(v)
+		case float32:
+			j.ID = int(v)
+		case float64:
+			j.ID = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 1: // ForeignKey
+		if v, ok := value.(int); ok {
+			j.ForeignKey = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			j.ForeignKey = int(v)
+		case int16:
+			j.ForeignKey = int(v)
+		case int32:
+			j.ForeignKey = int(v)
+		case int64:
+			j.ForeignKey = int(v)
+		case uint:
+			j.ForeignKey = int(v)
+		case uint8:
+			j.ForeignKey = int(v)
+		case uint16:
+			j.ForeignKey = int(v)
+		case uint32:
+			j.ForeignKey = int(v)
+		case uint64:
+			j.ForeignKey = int(v)
+		case float32:
+			j.ForeignKey = int(v)
+		case float64:
+			j.ForeignKey = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 2: // MtMForeignKey
+		if v, ok := value.(int); ok {
+			j.MtMForeignKey = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			j.MtMForeignKey = int(v)
+		case int16:
+			j.MtMForeignKey = int(v)
+		case int32:
+			j.MtMForeignKey = int(v)
+		case int64:
+			j.MtMForeignKey = int(v)
+		case uint:
+			j.MtMForeignKey = int(v)
+		case uint8:
+			j.MtMForeignKey = int(v)
+		case uint16:
+			j.MtMForeignKey = int(v)
+		case uint32:
+			j.MtMForeignKey = int(v)
+		case uint64:
+			j.MtMForeignKey = int(v)
+		case float32:
+			j.MtMForeignKey = int(v)
+		case float64:
+			j.MtMForeignKey = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'JoinModel'", field.Name())
+	}
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (j *JoinModel) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 1: // ForeignKey
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 2: // MtMForeignKey
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: JoinModel'", field.Name())
+}
+
+// Compile time check if ManyToManyModel implements mapping.Model interface.
+var _ mapping.Model = &ManyToManyModel{}
+
+// NeuronCollectionName implements mapping.Model interface method.
+// Returns the name of the collection for the 'ManyToManyModel'.
+func (m *ManyToManyModel) NeuronCollectionName() string {
+	return "many_to_many_models"
+}
+
+// IsPrimaryKeyZero implements mapping.Model interface method.
+func (m *ManyToManyModel) IsPrimaryKeyZero() bool {
+	return m.ID == 0
+}
+
+// GetPrimaryKeyValue implements mapping.Model interface method.
+func (m *ManyToManyModel) GetPrimaryKeyValue() interface{} {
+	return m.ID
+}
+
+// GetPrimaryKeyStringValue implements mapping.Model interface method.
+func (m *ManyToManyModel) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(m.ID), 10), nil
+}
+
+// GetPrimaryKeyAddress implements mapping.Model interface method.
+func (m *ManyToManyModel) GetPrimaryKeyAddress() interface{} {
+	return &m.ID
+}
+
+// GetPrimaryKeyHashableValue implements mapping.Model interface method.
+func (m *ManyToManyModel) GetPrimaryKeyHashableValue() interface{} {
+	return m.ID
+}
+
+// GetPrimaryKeyZeroValue implements mapping.Model interface method.
+func (m *ManyToManyModel) GetPrimaryKeyZeroValue() interface{} {
+	return 0
+}
+
+// SetPrimaryKey implements mapping.Model interface method.
+func (m *ManyToManyModel) SetPrimaryKeyValue(value interface{}) error {
+	if v, ok := value.(int); ok {
+		m.ID = v
+		return nil
+	}
+	// Check alternate types for given field.
+	switch valueType := value.(type) {
+	case int8:
+		m.ID = int(valueType)
+	case int16:
+		m.ID = int(valueType)
+	case int32:
+		m.ID = int(valueType)
+	case int64:
+		m.ID = int(valueType)
+	case uint:
+		m.ID = int(valueType)
+	case uint8:
+		m.ID = int(valueType)
+	case uint16:
+		m.ID = int(valueType)
+	case uint32:
+		m.ID = int(valueType)
+	case uint64:
+		m.ID = int(valueType)
+	case float32:
+		m.ID = int(valueType)
+	case float64:
+		m.ID = int(valueType)
+	default:
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'ManyToManyModel'", value)
+	}
+	return nil
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (m *ManyToManyModel) SetPrimaryKeyStringValue(value string) error {
+	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	if err != nil {
+		return err
+	}
+	m.ID = int(tmp)
+	return nil
+}
+
+// Compile time check if ManyToManyModel implements mapping.Fielder interface.
+var _ mapping.Fielder = &ManyToManyModel{}
+
+// GetFieldsAddress gets the address of provided 'field'.
+func (m *ManyToManyModel) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return &m.ID, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: ManyToManyModel'", field.Name())
+}
+
+// GetFieldZeroValue implements mapping.Fielder interface.s
+func (m *ManyToManyModel) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return 0, nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+}
+
+// IsFieldZero implements mapping.Fielder interface.
+func (m *ManyToManyModel) IsFieldZero(field *mapping.StructField) (bool, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return m.ID == 0, nil
+	}
+	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+}
+
+// SetFieldZeroValue implements mapping.Fielder interface.s
+func (m *ManyToManyModel) SetFieldZeroValue(field *mapping.StructField) error {
+	switch field.Index[0] {
+	case 0: // ID
+		m.ID = 0
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+	return nil
+}
+
+// GetHashableFieldValue implements mapping.Fielder interface.
+func (m *ManyToManyModel) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return m.ID, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'ManyToManyModel'", field.Name())
+}
+
+// GetFieldValue implements mapping.Fielder interface.
+func (m *ManyToManyModel) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return m.ID, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: ManyToManyModel'", field.Name())
+}
+
+// SetFieldValue implements mapping.Fielder interface.
+func (m *ManyToManyModel) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+	switch field.Index[0] {
+	case 0: // ID
+		if v, ok := value.(int); ok {
+			m.ID = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			m.ID = int(v)
+		case int16:
+			m.ID = int(v)
+		case int32:
+			m.ID = int(v)
+		case int64:
+			m.ID = int(v)
+		case uint:
+			m.ID = int(v)
+		case uint8:
+			m.ID = int(v)
+		case uint16:
+			m.ID = int(v)
+		case uint32:
+			m.ID = int(v)
+		case uint64:
+			m.ID = int(v)
+		case float32:
+			m.ID = int(v)
+		case float64:
+			m.ID = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'ManyToManyModel'", field.Name())
+	}
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (m *ManyToManyModel) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: ManyToManyModel'", field.Name())
+}
+
+// Compile time check for the mapping.MultiRelationer interface implementation.
+var _ mapping.MultiRelationer = &ManyToManyModel{}
+
+// AddRelationModel implements mapping.MultiRelationer interface.
+func (m *ManyToManyModel) AddRelationModel(relation *mapping.StructField, model mapping.Model) error {
+	switch relation.Index[0] {
+	case 1: // Many2Many
+		relatedModel, ok := model.(*RelatedModel)
+		if !ok {
+			return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid value type: '%T'  for the field: 'Many2Many'", model)
+		}
+		m.Many2Many = append(m.Many2Many, relatedModel)
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%T' for the model 'ManyToManyModel'", model)
+	}
+	return nil
+}
+
+// GetRelationModels implements mapping.MultiRelationer interface.
+func (m *ManyToManyModel) GetRelationModels(relation *mapping.StructField) (models []mapping.Model, err error) {
+	switch relation.Index[0] {
+	case 1: // Many2Many
+		for _, model := range m.Many2Many {
+			models = append(models, model)
+		}
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, m)
+	}
+	return models, nil
+}
+
+// GetRelationModelAt implements mapping.MultiRelationer interface.
+func (m *ManyToManyModel) GetRelationModelAt(relation *mapping.StructField, index int) (models mapping.Model, err error) {
+	switch relation.Index[0] {
+	case 1: // Many2Many
+		if index > len(m.Many2Many)-1 {
+			return nil, errors.Wrapf(mapping.ErrInvalidRelationIndex, "index out of possible range. Model: 'ManyToManyModel', Field Many2Many")
+		}
+		return m.Many2Many[index], nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, m)
+	}
+}
+
+// GetRelationLen implements mapping.MultiRelationer interface.
+func (m *ManyToManyModel) GetRelationLen(relation *mapping.StructField) (int, error) {
+	switch relation.Index[0] {
+	case 1: // Many2Many
+		return len(m.Many2Many), nil
+	default:
+		return 0, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, m)
+	}
+}
+
+// SetRelationModels implements mapping.MultiRelationer interface.
+func (m *ManyToManyModel) SetRelationModels(relation *mapping.StructField, models ...mapping.Model) error {
+	switch relation.Index[0] {
+	case 1: // Many2Many
+		temp := make([]*RelatedModel, len(models))
+		for i, model := range models {
+			relatedModel, ok := model.(*RelatedModel)
+			if !ok {
+				return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid value type: '%T'  for the field: 'Many2Many'", model)
+			}
+			temp[i] = relatedModel
+		}
+		m.Many2Many = temp
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for the model 'ManyToManyModel'", relation.String())
+	}
+	return nil
+}
+
+// Compile time check if Model implements mapping.Model interface.
+var _ mapping.Model = &Model{}
+
+// NeuronCollectionName implements mapping.Model interface method.
+// Returns the name of the collection for the 'Model'.
+func (m *Model) NeuronCollectionName() string {
+	return "models"
+}
+
+// IsPrimaryKeyZero implements mapping.Model interface method.
+func (m *Model) IsPrimaryKeyZero() bool {
+	return m.ID == 0
+}
+
+// GetPrimaryKeyValue implements mapping.Model interface method.
+func (m *Model) GetPrimaryKeyValue() interface{} {
+	return m.ID
+}
+
+// GetPrimaryKeyStringValue implements mapping.Model interface method.
+func (m *Model) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(m.ID), 10), nil
+}
+
+// GetPrimaryKeyAddress implements mapping.Model interface method.
+func (m *Model) GetPrimaryKeyAddress() interface{} {
+	return &m.ID
+}
+
+// GetPrimaryKeyHashableValue implements mapping.Model interface method.
+func (m *Model) GetPrimaryKeyHashableValue() interface{} {
+	return m.ID
+}
+
+// GetPrimaryKeyZeroValue implements mapping.Model interface method.
+func (m *Model) GetPrimaryKeyZeroValue() interface{} {
+	return 0
+}
+
+// SetPrimaryKey implements mapping.Model interface method.
+func (m *Model) SetPrimaryKeyValue(value interface{}) error {
+	if v, ok := value.(int); ok {
+		m.ID = v
+		return nil
+	}
+	// Check alternate types for given field.
+	switch valueType := value.(type) {
+	case int8:
+		m.ID = int(valueType)
+	case int16:
+		m.ID = int(valueType)
+	case int32:
+		m.ID = int(valueType)
+	case int64:
+		m.ID = int(valueType)
+	case uint:
+		m.ID = int(valueType)
+	case uint8:
+		m.ID = int(valueType)
+	case uint16:
+		m.ID = int(valueType)
+	case uint32:
+		m.ID = int(valueType)
+	case uint64:
+		m.ID = int(valueType)
+	case float32:
+		m.ID = int(valueType)
+	case float64:
+		m.ID = int(valueType)
+	default:
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'Model'", value)
+	}
+	return nil
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (m *Model) SetPrimaryKeyStringValue(value string) error {
+	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	if err != nil {
+		return err
+	}
+	m.ID = int(tmp)
+	return nil
+}
+
+// SetFrom implements FromSetter interface.
+func (m *Model) SetFrom(model mapping.Model) error {
+	if model == nil {
+		return errors.Wrap(query.ErrInvalidInput, "provided nil model to set from")
+	}
+	from, ok := model.(*Model)
+	if !ok {
+		return errors.WrapDetf(mapping.ErrModelNotMatch, "provided model doesn't match the input: %T", model)
+	}
+	*m = *from
+	return nil
+}
+
+// Compile time check if Model implements mapping.Fielder interface.
+var _ mapping.Fielder = &Model{}
+
+// GetFieldsAddress gets the address of provided 'field'.
+func (m *Model) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return &m.ID, nil
+	case 1: // AttrString
+		return &m.AttrString, nil
+	case 2: // StringPtr
+		return &m.StringPtr, nil
+	case 3: // Int
+		return &m.Int, nil
+	case 4: // CreatedAt
+		return &m.CreatedAt, nil
+	case 5: // UpdatedAt
+		return &m.UpdatedAt, nil
+	case 6: // DeletedAt
+		return &m.DeletedAt, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Model'", field.Name())
+}
+
+// GetFieldZeroValue implements mapping.Fielder interface.s
+func (m *Model) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return 0, nil
+	case 1: // AttrString
+		return "", nil
+	case 2: // StringPtr
+		return nil, nil
+	case 3: // Int
+		return 0, nil
+	case 4: // CreatedAt
+		return time.Time{}, nil
+	case 5: // UpdatedAt
+		return nil, nil
+	case 6: // DeletedAt
+		return nil, nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+}
+
+// IsFieldZero implements mapping.Fielder interface.
+func (m *Model) IsFieldZero(field *mapping.StructField) (bool, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return m.ID == 0, nil
+	case 1: // AttrString
+		return m.AttrString == "", nil
+	case 2: // StringPtr
+		return m.StringPtr == nil, nil
+	case 3: // Int
+		return m.Int == 0, nil
+	case 4: // CreatedAt
+		return m.CreatedAt == time.Time{}, nil
+	case 5: // UpdatedAt
+		return m.UpdatedAt == nil, nil
+	case 6: // DeletedAt
+		return m.DeletedAt == nil, nil
+	}
+	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+}
+
+// SetFieldZeroValue implements mapping.Fielder interface.s
+func (m *Model) SetFieldZeroValue(field *mapping.StructField) error {
+	switch field.Index[0] {
+	case 0: // ID
+		m.ID = 0
+	case 1: // AttrString
+		m.AttrString = ""
+	case 2: // StringPtr
+		m.StringPtr = nil
+	case 3: // Int
+		m.Int = 0
+	case 4: // CreatedAt
+		m.CreatedAt = time.Time{}
+	case 5: // UpdatedAt
+		m.UpdatedAt = nil
+	case 6: // DeletedAt
+		m.DeletedAt = nil
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+	return nil
+}
+
+// GetHashableFieldValue implements mapping.Fielder interface.
+func (m *Model) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return m.ID, nil
+	case 1: // AttrString
+		return m.AttrString, nil
+	case 2: // StringPtr
+		if m.StringPtr == nil {
+			return nil, nil
+		}
+		return *m.StringPtr, nil
+	case 3: // Int
+		return m.Int, nil
+	case 4: // CreatedAt
+		return m.CreatedAt, nil
+	case 5: // UpdatedAt
+		if m.UpdatedAt == nil {
+			return nil, nil
+		}
+		return *m.UpdatedAt, nil
+	case 6: // DeletedAt
+		if m.DeletedAt == nil {
+			return nil, nil
+		}
+		return *m.DeletedAt, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'Model'", field.Name())
+}
+
+// GetFieldValue implements mapping.Fielder interface.
+func (m *Model) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return m.ID, nil
+	case 1: // AttrString
+		return m.AttrString, nil
+	case 2: // StringPtr
+		return m.StringPtr, nil
+	case 3: // Int
+		return m.Int, nil
+	case 4: // CreatedAt
+		return m.CreatedAt, nil
+	case 5: // UpdatedAt
+		return m.UpdatedAt, nil
+	case 6: // DeletedAt
+		return m.DeletedAt, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Model'", field.Name())
+}
+
+// SetFieldValue implements mapping.Fielder interface.
+func (m *Model) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+	switch field.Index[0] {
+	case 0: // ID
+		if v, ok := value.(int); ok {
+			m.ID = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			m.ID = int(v)
+		case int16:
+			m.ID = int(v)
+		case int32:
+			m.ID = int(v)
+		case int64:
+			m.ID = int(v)
+		case uint:
+			m.ID = int(v)
+		case uint8:
+			m.ID = int(v)
+		case uint16:
+			m.ID = int(v)
+		case uint32:
+			m.ID = int(v)
+		case uint64:
+			m.ID = int(v)
+		case float32:
+			m.ID = int(v)
+		case float64:
+			m.ID = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 1: // AttrString
+		if v, ok := value.(string); ok {
+			m.AttrString = v
+			return nil
+		}
+
+		// Check alternate types for the AttrString.
+		if v, ok := value.([]byte); ok {
+			m.AttrString = string(v)
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	case 2: // StringPtr
+		if value == nil {
+			m.StringPtr = nil
+			return nil
+		}
+		if v, ok := value.(*string); ok {
+			m.StringPtr = v
+			return nil
+		}
+		// Check if it is non-pointer value.
+		if v, ok := value.(string); ok {
+			m.StringPtr = &v
+			return nil
+		}
+
+		// Check alternate types for the StringPtr.
+		if v, ok := value.([]byte); ok {
+			temp := string(v)
+			m.StringPtr = &temp
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	case 3: // Int
+		if v, ok := value.(int); ok {
+			m.Int = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			m.Int = int(v)
+		case int16:
+			m.Int = int(v)
+		case int32:
+			m.Int = int(v)
+		case int64:
+			m.Int = int(v)
+		case uint:
+			m.Int = int(v)
+		case uint8:
+			m.Int = int(v)
+		case uint16:
+			m.Int = int(v)
+		case uint32:
+			m.Int = int(v)
+		case uint64:
+			m.Int = int(v)
+		case float32:
+			m.Int = int(v)
+		case float64:
+			m.Int = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 4: // CreatedAt
+		if v, ok := value.(time.Time); ok {
+			m.CreatedAt = v
+			return nil
+		}
+
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	case 5: // UpdatedAt
+		if value == nil {
+			m.UpdatedAt = nil
+			return nil
+		}
+		if v, ok := value.(*time.Time); ok {
+			m.UpdatedAt = v
+			return nil
+		}
+		// Check if it is non-pointer value.
+		if v, ok := value.(time.Time); ok {
+			m.UpdatedAt = &v
+			return nil
+		}
+
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	case 6: // DeletedAt
+		if value == nil {
+			m.DeletedAt = nil
+			return nil
+		}
+		if v, ok := value.(*time.Time); ok {
+			m.DeletedAt = v
+			return nil
+		}
+		// Check if it is non-pointer value.
+		if v, ok := value.(time.Time); ok {
+			m.DeletedAt = &v
+			return nil
+		}
+
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'Model'", field.Name())
+	}
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (m *Model) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 1: // AttrString
+		return value, nil
+	case 2: // StringPtr
+		return value, nil
+	case 3: // Int
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 4: // CreatedAt
+		temp := m.CreatedAt
+		if err := m.CreatedAt.UnmarshalText([]byte(value)); err != nil {
+			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'CreatedAt' value: '%v' to parse string. Err: %v", m.CreatedAt, err)
+		}
+		bt, err := m.CreatedAt.MarshalText()
+		if err != nil {
+			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'CreatedAt' value: '%v' to parse string. Err: %v", m.CreatedAt, err)
+		}
+		m.CreatedAt = temp
+		return string(bt), nil
+	case 5: // UpdatedAt
+		var base time.Time
 		temp := &base
 		if err := temp.UnmarshalText([]byte(value)); err != nil {
 			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'UpdatedAt' value: '%v' to parse string. Err: %v", m.UpdatedAt, err)
 		}
-		bt, err := temp.MarshalText()
-		if err != nil {
-			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'UpdatedAt' value: '%v' to parse string. Err: %v", m.UpdatedAt, err)
+		bt, err := temp.MarshalText()
+		if err != nil {
+			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'UpdatedAt' value: '%v' to parse string. Err: %v", m.UpdatedAt, err)
+		}
+
+		return string(bt), nil
+	case 6: // DeletedAt
+		var base time.Time
+		temp := &base
+		if err := temp.UnmarshalText([]byte(value)); err != nil {
+			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'DeletedAt' value: '%v' to parse string. Err: %v", m.DeletedAt, err)
+		}
+		bt, err := temp.MarshalText()
+		if err != nil {
+			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'DeletedAt' value: '%v' to parse string. Err: %v", m.DeletedAt, err)
+		}
+
+		return string(bt), nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Model'", field.Name())
+}
+
+// Compile time check if OmitModel implements mapping.Model interface.
+var _ mapping.Model = &OmitModel{}
+
+// NeuronCollectionName implements mapping.Model interface method.
+// Returns the name of the collection for the 'OmitModel'.
+func (o *OmitModel) NeuronCollectionName() string {
+	return "omit_models"
+}
+
+// IsPrimaryKeyZero implements mapping.Model interface method.
+func (o *OmitModel) IsPrimaryKeyZero() bool {
+	return o.ID == 0
+}
+
+// GetPrimaryKeyValue implements mapping.Model interface method.
+func (o *OmitModel) GetPrimaryKeyValue() interface{} {
+	return o.ID
+}
+
+// GetPrimaryKeyStringValue implements mapping.Model interface method.
+func (o *OmitModel) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(o.ID), 10), nil
+}
+
+// GetPrimaryKeyAddress implements mapping.Model interface method.
+func (o *OmitModel) GetPrimaryKeyAddress() interface{} {
+	return &o.ID
+}
+
+// GetPrimaryKeyHashableValue implements mapping.Model interface method.
+func (o *OmitModel) GetPrimaryKeyHashableValue() interface{} {
+	return o.ID
+}
+
+// GetPrimaryKeyZeroValue implements mapping.Model interface method.
+func (o *OmitModel) GetPrimaryKeyZeroValue() interface{} {
+	return 0
+}
+
+// SetPrimaryKey implements mapping.Model interface method.
+func (o *OmitModel) SetPrimaryKeyValue(value interface{}) error {
+	if v, ok := value.(int); ok {
+		o.ID = v
+		return nil
+	}
+	// Check alternate types for given field.
+	switch valueType := value.(type) {
+	case int8:
+		o.ID = int(valueType)
+	case int16:
+		o.ID = int(valueType)
+	case int32:
+		o.ID = int(valueType)
+	case int64:
+		o.ID = int(valueType)
+	case uint:
+		o.ID = int(valueType)
+	case uint8:
+		o.ID = int(valueType)
+	case uint16:
+		o.ID = int(valueType)
+	case uint32:
+		o.ID = int(valueType)
+	case uint64:
+		o.ID = int(valueType)
+	case float32:
+		o.ID = int(valueType)
+	case float64:
+		o.ID = int(valueType)
+	default:
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'OmitModel'", value)
+	}
+	return nil
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (o *OmitModel) SetPrimaryKeyStringValue(value string) error {
+	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	if err != nil {
+		return err
+	}
+	o.ID = int(tmp)
+	return nil
+}
+
+// SetFrom implements FromSetter interface.
+func (o *OmitModel) SetFrom(model mapping.Model) error {
+	if model == nil {
+		return errors.Wrap(query.ErrInvalidInput, "provided nil model to set from")
+	}
+	from, ok := model.(*OmitModel)
+	if !ok {
+		return errors.WrapDetf(mapping.ErrModelNotMatch, "provided model doesn't match the input: %T", model)
+	}
+	*o = *from
+	return nil
+}
+
+// Compile time check if OmitModel implements mapping.Fielder interface.
+var _ mapping.Fielder = &OmitModel{}
+
+// GetFieldsAddress gets the address of provided 'field'.
+func (o *OmitModel) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return &o.ID, nil
+	case 1: // OmitField
+		return &o.OmitField, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: OmitModel'", field.Name())
+}
+
+// GetFieldZeroValue implements mapping.Fielder interface.s
+func (o *OmitModel) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return 0, nil
+	case 1: // OmitField
+		return "", nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+}
+
+// IsFieldZero implements mapping.Fielder interface.
+func (o *OmitModel) IsFieldZero(field *mapping.StructField) (bool, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return o.ID == 0, nil
+	case 1: // OmitField
+		return o.OmitField == "", nil
+	}
+	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+}
+
+// SetFieldZeroValue implements mapping.Fielder interface.s
+func (o *OmitModel) SetFieldZeroValue(field *mapping.StructField) error {
+	switch field.Index[0] {
+	case 0: // ID
+		o.ID = 0
+	case 1: // OmitField
+		o.OmitField = ""
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+	return nil
+}
+
+// GetHashableFieldValue implements mapping.Fielder interface.
+func (o *OmitModel) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return o.ID, nil
+	case 1: // OmitField
+		return o.OmitField, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'OmitModel'", field.Name())
+}
+
+// GetFieldValue implements mapping.Fielder interface.
+func (o *OmitModel) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return o.ID, nil
+	case 1: // OmitField
+		return o.OmitField, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: OmitModel'", field.Name())
+}
+
+// SetFieldValue implements mapping.Fielder interface.
+func (o *OmitModel) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+	switch field.Index[0] {
+	case 0: // ID
+		if v, ok := value.(int); ok {
+			o.ID = v
+			return nil
+		}
+
+		switch v := value.(type) {
+		case int8:
+			o.ID = int(v)
+		case int16:
+			o.ID = int(v)
+		case int32:
+			o.ID = int(v)
+		case int64:
+			o.ID = int(v)
+		case uint:
+			o.ID = int(v)
+		case uint8:
+			o.ID = int(v)
+		case uint16:
+			o.ID = int(v)
+		case uint32:
+			o.ID = int(v)
+		case uint64:
+			o.ID = int(v)
+		case float32:
+			o.ID = int(v)
+		case float64:
+			o.ID = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 1: // OmitField
+		if v, ok := value.(string); ok {
+			o.OmitField = v
+			return nil
+		}
+
+		// Check alternate types for the OmitField.
+		if v, ok := value.([]byte); ok {
+			o.OmitField = string(v)
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'OmitModel'", field.Name())
+	}
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (o *OmitModel) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 1: // OmitField
+		return value, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: OmitModel'", field.Name())
+}
+
+// Compile time check if Post implements mapping.Model interface.
+var _ mapping.Model = &Post{}
+
+// NeuronCollectionName implements mapping.Model interface method.
+// Returns the name of the collection for the 'Post'.
+func (p *Post) NeuronCollectionName() string {
+	return "posts"
+}
+
+// IsPrimaryKeyZero implements mapping.Model interface method.
+func (p *Post) IsPrimaryKeyZero() bool {
+	return p.ID == 0
+}
+
+// GetPrimaryKeyValue implements mapping.Model interface method.
+func (p *Post) GetPrimaryKeyValue() interface{} {
+	return p.ID
+}
+
+// GetPrimaryKeyStringValue implements mapping.Model interface method.
+func (p *Post) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatUint(p.ID, 10), nil
+}
+
+// GetPrimaryKeyAddress implements mapping.Model interface method.
+func (p *Post) GetPrimaryKeyAddress() interface{} {
+	return &p.ID
+}
+
+// GetPrimaryKeyHashableValue implements mapping.Model interface method.
+func (p *Post) GetPrimaryKeyHashableValue() interface{} {
+	return p.ID
+}
+
+// GetPrimaryKeyZeroValue implements mapping.Model interface method.
+func (p *Post) GetPrimaryKeyZeroValue() interface{} {
+	return 0
+}
+
+// SetPrimaryKey implements mapping.Model interface method.
+func (p *Post) SetPrimaryKeyValue(value interface{}) error {
+	if v, ok := value.(uint64); ok {
+		p.ID = v
+		return nil
+	}
+	// Check alternate types for given field.
+	switch valueType := value.(type) {
+	case int:
+		p.ID = uint64(valueType)
+	case int8:
+		p.ID = uint64(valueType)
+	case int16:
+		p.ID = uint64(valueType)
+	case int32:
+		p.ID = uint64(valueType)
+	case int64:
+		p.ID = uint64(valueType)
+	case uint:
+		p.ID = uint64(valueType)
+	case uint8:
+		p.ID = uint64(valueType)
+	case uint16:
+		p.ID = uint64(valueType)
+	case uint32:
+		p.ID = uint64(valueType)
+	case float32:
+		p.ID = uint64(valueType)
+	case float64:
+		p.ID = uint64(valueType)
+	default:
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'Post'", value)
+	}
+	return nil
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (p *Post) SetPrimaryKeyStringValue(value string) error {
+	tmp, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return err
+	}
+	p.ID = tmp
+	return nil
+}
+
+// Compile time check if Post implements mapping.Fielder interface.
+var _ mapping.Fielder = &Post{}
+
+// GetFieldsAddress gets the address of provided 'field'.
+func (p *Post) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return &p.ID, nil
+	case 1: // BlogID
+		return &p.BlogID, nil
+	case 2: // Title
+		return &p.Title, nil
+	case 3: // Body
+		return &p.Body, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Post'", field.Name())
+}
+
+// GetFieldZeroValue implements mapping.Fielder interface.s
+func (p *Post) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return 0, nil
+	case 1: // BlogID
+		return 0, nil
+	case 2: // Title
+		return "", nil
+	case 3: // Body
+		return "", nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+}
+
+// IsFieldZero implements mapping.Fielder interface.
+func (p *Post) IsFieldZero(field *mapping.StructField) (bool, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return p.ID == 0, nil
+	case 1: // BlogID
+		return p.BlogID == 0, nil
+	case 2: // Title
+		return p.Title == "", nil
+	case 3: // Body
+		return p.Body == "", nil
+	}
+	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+}
+
+// SetFieldZeroValue implements mapping.Fielder interface.s
+func (p *Post) SetFieldZeroValue(field *mapping.StructField) error {
+	switch field.Index[0] {
+	case 0: // ID
+		p.ID = 0
+	case 1: // BlogID
+		p.BlogID = 0
+	case 2: // Title
+		p.Title = ""
+	case 3: // Body
+		p.Body = ""
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+	return nil
+}
+
+// GetHashableFieldValue implements mapping.Fielder interface.
+func (p *Post) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return p.ID, nil
+	case 1: // BlogID
+		return p.BlogID, nil
+	case 2: // Title
+		return p.Title, nil
+	case 3: // Body
+		return p.Body, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'Post'", field.Name())
+}
+
+// GetFieldValue implements mapping.Fielder interface.
+func (p *Post) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return p.ID, nil
+	case 1: // BlogID
+		return p.BlogID, nil
+	case 2: // Title
+		return p.Title, nil
+	case 3: // Body
+		return p.Body, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Post'", field.Name())
+}
+
+// SetFieldValue implements mapping.Fielder interface.
+func (p *Post) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+	switch field.Index[0] {
+	case 0: // ID
+		if v, ok := value.(uint64); ok {
+			p.ID = v
+			return nil
 		}
 
-		return string(bt), nil
-	case 6: // DeletedAt
-		var base time.Time
-		temp := &base
-		if err := temp.UnmarshalText([]byte(value)); err != nil {
-			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'DeletedAt' value: '%v' to parse string. Err: %v", m.DeletedAt, err)
+		switch v := value.(type) {
+		case int:
+			p.ID = uint64(v)
+		case int8:
+			p.ID = uint64(v)
+		case int16:
+			p.ID = uint64(v)
+		case int32:
+			p.ID = uint64(v)
+		case int64:
+			p.ID = uint64(v)
+		case uint:
+			p.ID = uint64(v)
+		case uint8:
+			p.ID = uint64(v)
+		case uint16:
+			p.ID = uint64(v)
+		case uint32:
+			p.ID = uint64(v)
+		case float32:
+			p.ID = uint64(v)
+		case float64:
+			p.ID = uint64(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
 		}
-		bt, err := temp.MarshalText()
-		if err != nil {
-			return "", errors.Wrapf(mapping.ErrFieldValue, "invalid field 'DeletedAt' value: '%v' to parse string. Err: %v", m.DeletedAt, err)
+		return nil
+	case 1: // BlogID
+		if v, ok := value.(int); ok {
+			p.BlogID = v
+			return nil
 		}
 
-		return string(bt), nil
+		switch v := value.(type) {
+		case int8:
+			p.BlogID = int(v)
+		case int16:
+			p.BlogID = int(v)
+		case int32:
+			p.BlogID = int(v)
+		case int64:
+			p.BlogID = int(v)
+		case uint:
+			p.BlogID = int(v)
+		case uint8:
+			p.BlogID = int(v)
+		case uint16:
+			p.BlogID = int(v)
+		case uint32:
+			p.BlogID = int(v)
+		case uint64:
+			p.BlogID = int(v)
+		case float32:
+			p.BlogID = int(v)
+		case float64:
+			p.BlogID = int(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 2: // Title
+		if v, ok := value.(string); ok {
+			p.Title = v
+			return nil
+		}
+
+		// Check alternate types for the Title.
+		if v, ok := value.([]byte); ok {
+			p.Title = string(v)
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	case 3: // Body
+		if v, ok := value.(string); ok {
+			p.Body = v
+			return nil
+		}
+
+		// Check alternate types for the Body.
+		if v, ok := value.([]byte); ok {
+			p.Body = string(v)
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'Post'", field.Name())
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Model'", field.Name())
 }
 
-// Compile time check if OmitModel implements mapping.Model interface.
-var _ mapping.Model = &OmitModel{}
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (p *Post) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return strconv.ParseUint(value, 10, 64)
+	case 1: // BlogID
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 2: // Title
+		return value, nil
+	case 3: // Body
+		return value, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: Post'", field.Name())
+}
+
+// Compile time check if Post implements mapping.SingleRelationer interface.
+var _ mapping.SingleRelationer = &Post{}
+
+// GetRelationModel implements mapping.SingleRelationer interface.
+func (p *Post) GetRelationModel(relation *mapping.StructField) (mapping.Model, error) {
+	switch relation.Index[0] {
+	case 5: // LatestComment
+		if p.LatestComment == nil {
+			return nil, nil
+		}
+		return p.LatestComment, nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, p)
+	}
+}
+
+// SetRelationModel implements mapping.SingleRelationer interface.
+func (p *Post) SetRelationModel(relation *mapping.StructField, model mapping.Model) error {
+	switch relation.Index[0] {
+	case 5: // LatestComment
+		if model == nil {
+			p.LatestComment = nil
+			return nil
+		} else if latestComment, ok := model.(*Comment); ok {
+			p.LatestComment = latestComment
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid model value: '%T' for relation LatestComment", model)
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, p)
+	}
+}
+
+// Compile time check for the mapping.MultiRelationer interface implementation.
+var _ mapping.MultiRelationer = &Post{}
+
+// AddRelationModel implements mapping.MultiRelationer interface.
+func (p *Post) AddRelationModel(relation *mapping.StructField, model mapping.Model) error {
+	switch relation.Index[0] {
+	case 4: // Comments
+		comment, ok := model.(*Comment)
+		if !ok {
+			return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid value type: '%T'  for the field: 'Comments'", model)
+		}
+		p.Comments = append(p.Comments, comment)
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%T' for the model 'Post'", model)
+	}
+	return nil
+}
+
+// GetRelationModels implements mapping.MultiRelationer interface.
+func (p *Post) GetRelationModels(relation *mapping.StructField) (models []mapping.Model, err error) {
+	switch relation.Index[0] {
+	case 4: // Comments
+		for _, model := range p.Comments {
+			models = append(models, model)
+		}
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, p)
+	}
+	return models, nil
+}
+
+// GetRelationModelAt implements mapping.MultiRelationer interface.
+func (p *Post) GetRelationModelAt(relation *mapping.StructField, index int) (models mapping.Model, err error) {
+	switch relation.Index[0] {
+	case 4: // Comments
+		if index > len(p.Comments)-1 {
+			return nil, errors.Wrapf(mapping.ErrInvalidRelationIndex, "index out of possible range. Model: 'Post', Field Comments")
+		}
+		return p.Comments[index], nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, p)
+	}
+}
+
+// GetRelationLen implements mapping.MultiRelationer interface.
+func (p *Post) GetRelationLen(relation *mapping.StructField) (int, error) {
+	switch relation.Index[0] {
+	case 4: // Comments
+		return len(p.Comments), nil
+	default:
+		return 0, errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for model: '%T'", relation, p)
+	}
+}
+
+// SetRelationModels implements mapping.MultiRelationer interface.
+func (p *Post) SetRelationModels(relation *mapping.StructField, models ...mapping.Model) error {
+	switch relation.Index[0] {
+	case 4: // Comments
+		temp := make([]*Comment, len(models))
+		for i, model := range models {
+			comment, ok := model.(*Comment)
+			if !ok {
+				return errors.Wrapf(mapping.ErrInvalidRelationValue, "provided invalid value type: '%T'  for the field: 'Comments'", model)
+			}
+			temp[i] = comment
+		}
+		p.Comments = temp
+	default:
+		return errors.Wrapf(mapping.ErrInvalidRelationField, "provided invalid relation: '%s' for the model 'Post'", relation.String())
+	}
+	return nil
+}
+
+// Compile time check if RelatedModel implements mapping.Model interface.
+var _ mapping.Model = &RelatedModel{}
 
 // NeuronCollectionName implements mapping.Model interface method.
-// Returns the name of the collection for the 'OmitModel'.
-func (o *OmitModel) NeuronCollectionName() string {
-	return "omit_models"
+// Returns the name of the collection for the 'RelatedModel'.
+func (r *RelatedModel) NeuronCollectionName() string {
+	return "related_models"
 }
 
 // IsPrimaryKeyZero implements mapping.Model interface method.
-func (o *OmitModel) IsPrimaryKeyZero() bool {
-	return o.ID == 0
+func (r *RelatedModel) IsPrimaryKeyZero() bool {
+	return r.ID == 0
 }
 
 // GetPrimaryKeyValue implements mapping.Model interface method.
-func (o *OmitModel) GetPrimaryKeyValue() interface{} {
-	return o.ID
+func (r *RelatedModel) GetPrimaryKeyValue() interface{} {
+	return r.ID
 }
 
 // GetPrimaryKeyStringValue implements mapping.Model interface method.
-func (o *OmitModel) GetPrimaryKeyStringValue() (string, error) {
-	return strconv.FormatInt(int64(o.ID), 10), nil
+func (r *RelatedModel) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(r.ID), 10), nil
 }
 
 // GetPrimaryKeyAddress implements mapping.Model interface method.
-func (o *OmitModel) GetPrimaryKeyAddress() interface{} {
-	return &o.ID
+func (r *RelatedModel) GetPrimaryKeyAddress() interface{} {
+	return &r.ID
 }
 
 // GetPrimaryKeyHashableValue implements mapping.Model interface method.
-func (o *OmitModel) GetPrimaryKeyHashableValue() interface{} {
-	return o.ID
+func (r *RelatedModel) GetPrimaryKeyHashableValue() interface{} {
+	return r.ID
 }
 
 // GetPrimaryKeyZeroValue implements mapping.Model interface method.
-func (o *OmitModel) GetPrimaryKeyZeroValue() interface{} {
+func (r *RelatedModel) GetPrimaryKeyZeroValue() interface{} {
 	return 0
 }
 
 // SetPrimaryKey implements mapping.Model interface method.
-func (o *OmitModel) SetPrimaryKeyValue(value interface{}) error {
+func (r *RelatedModel) SetPrimaryKeyValue(value interface{}) error {
 	if v, ok := value.(int); ok {
-		o.ID = v
+		r.ID = v
 		return nil
 	}
 	// Check alternate types for given field.
 	switch valueType := value.(type) {
 	case int8:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case int16:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case int32:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case int64:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case uint:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case uint8:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case uint16:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case uint32:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case uint64:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case float32:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	case float64:
-		o.ID = int(valueType)
+		r.ID = int(valueType)
 	default:
-		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'OmitModel'", value)
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'RelatedModel'", value)
 	}
 	return nil
 }
 
 // SetPrimaryKeyStringValue implements mapping.Model interface method.
-func (o *OmitModel) SetPrimaryKeyStringValue(value string) error {
+func (r *RelatedModel) SetPrimaryKeyStringValue(value string) error {
 	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
 	if err != nil {
 		return err
 	}
-	o.ID = int(tmp)
-	return nil
-}
-
-// SetFrom implements FromSetter interface.
-func (o *OmitModel) SetFrom(model mapping.Model) error {
-	if model == nil {
-		return errors.Wrap(query.ErrInvalidInput, "provided nil model to set from")
-	}
-	from, ok := model.(*OmitModel)
-	if !ok {
-		return errors.WrapDetf(mapping.ErrModelNotMatch, "provided model doesn't match the input: %T", model)
-	}
-	*o = *from
+	r.ID = int(tmp)
 	return nil
 }
 
-// Compile time check if OmitModel implements mapping.Fielder interface.
-var _ mapping.Fielder = &OmitModel{}
+// Compile time check if RelatedModel implements mapping.Fielder interface.
+var _ mapping.Fielder = &RelatedModel{}
 
 // GetFieldsAddress gets the address of provided 'field'.
-func (o *OmitModel) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+func (r *RelatedModel) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return &o.ID, nil
-	case 1: // OmitField
-		return &o.OmitField, nil
+		return &r.ID, nil
+	case 1: // FloatField
+		return &r.FloatField, nil
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: OmitModel'", field.Name())
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: RelatedModel'", field.Name())
 }
 
 // GetFieldZeroValue implements mapping.Fielder interface.s
-func (o *OmitModel) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+func (r *RelatedModel) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
 		return 0, nil
-	case 1: // OmitField
-		return "", nil
+	case 1: // FloatField
+		return 0, nil
 	default:
 		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
 	}
 }
 
 // IsFieldZero implements mapping.Fielder interface.
-func (o *OmitModel) IsFieldZero(field *mapping.StructField) (bool, error) {
+func (r *RelatedModel) IsFieldZero(field *mapping.StructField) (bool, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return o.ID == 0, nil
-	case 1: // OmitField
-		return o.OmitField == "", nil
+		return r.ID == 0, nil
+	case 1: // FloatField
+		return r.FloatField == 0, nil
 	}
 	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
 }
 
 // SetFieldZeroValue implements mapping.Fielder interface.s
-func (o *OmitModel) SetFieldZeroValue(field *mapping.StructField) error {
+func (r *RelatedModel) SetFieldZeroValue(field *mapping.StructField) error {
 	switch field.Index[0] {
 	case 0: // ID
-		o.ID = 0
-	case 1: // OmitField
-		o.OmitField = ""
+		r.ID = 0
+	case 1: // FloatField
+		r.FloatField = 0
 	default:
 		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
 	}
@@ -881,89 +2784,110 @@ func (o *OmitModel) SetFieldZeroValue(field *mapping.StructField) error {
 }
 
 // GetHashableFieldValue implements mapping.Fielder interface.
-func (o *OmitModel) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+func (r *RelatedModel) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return o.ID, nil
-	case 1: // OmitField
-		return o.OmitField, nil
+		return r.ID, nil
+	case 1: // FloatField
+		return r.FloatField, nil
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'OmitModel'", field.Name())
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'RelatedModel'", field.Name())
 }
 
 // GetFieldValue implements mapping.Fielder interface.
-func (o *OmitModel) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+func (r *RelatedModel) GetFieldValue(field *mapping.StructField) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
-		return o.ID, nil
-	case 1: // OmitField
-		return o.OmitField, nil
+		return r.ID, nil
+	case 1: // FloatField
+		return r.FloatField, nil
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: OmitModel'", field.Name())
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: RelatedModel'", field.Name())
 }
 
 // SetFieldValue implements mapping.Fielder interface.
-func (o *OmitModel) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+func (r *RelatedModel) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
 	switch field.Index[0] {
 	case 0: // ID
 		if v, ok := value.(int); ok {
-			o.ID = v
+			r.ID = v
 			return nil
 		}
 
 		switch v := value.(type) {
 		case int8:
-			o.ID = int(v)
+			r.ID = int(v)
 		case int16:
-			o.ID = int(v)
+			r.ID = int(v)
 		case int32:
-			o.ID = int(v)
+			r.ID = int(v)
 		case int64:
-			o.ID = int(v)
+			r.ID = int(v)
 		case uint:
-			o.ID = int(v)
+			r.ID = int(v)
 		case uint8:
-			o.ID = int(v)
+			r.ID = int(v)
 		case uint16:
-			o.ID = int(v)
+			r.ID = int(v)
 		case uint32:
-			o.ID = int(v)
+			r.ID = int(v)
 		case uint64:
-			o.ID = int(v)
+			r.ID = int(v)
 		case float32:
-			o.ID = int(v)
+			r.ID = int(v)
 		case float64:
-			o.ID = int(v)
+			r.ID = int(v)
 		default:
 			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
 		}
 		return nil
-	case 1: // OmitField
-		if v, ok := value.(string); ok {
-			o.OmitField = v
+	case 1: // FloatField
+		if v, ok := value.(float64); ok {
+			r.FloatField = v
 			return nil
 		}
 
-		// Check alternate types for the OmitField.
-		if v, ok := value.([]byte); ok {
-			o.OmitField = string(v)
-			return nil
+		switch v := value.(type) {
+		case int:
+			r.FloatField = float64(v)
+		case int8:
+			r.FloatField = float64(v)
+		case int16:
+			r.FloatField = float64(v)
+		case int32:
+			r.FloatField = float64(v)
+		case int64:
+			r.FloatField = float64(v)
+		case uint:
+			r.FloatField = float64(v)
+		case uint8:
+			r.FloatField = float64(v)
+		case uint16:
+			r.FloatField = float64(v)
+		case uint32:
+			r.FloatField = float64(v)
+		case uint64:
+			r.FloatField = float64(v)
+		case float32:
+			r.FloatField = float64(v)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
 		}
-		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		return nil
 	default:
-		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'OmitModel'", field.Name())
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'RelatedModel'", field.Name())
 	}
 }
 
 // SetPrimaryKeyStringValue implements mapping.Model interface method.
-func (o *OmitModel) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+func (r *RelatedModel) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
 	switch field.Index[0] {
 	case 0: // ID
 		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
-	case 1: // OmitField
-		return value, nil
+	case 1: // FloatField
+		return strconv.ParseFloat(value, 64)
 	}
-	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: OmitModel'", field.Name())
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: RelatedModel'", field.Name())
 }
 
 // Compile time check if SimpleModel implements mapping.Model interface.
@@ -1242,3 +3166,284 @@ func (s *SimpleModel) ParseFieldsStringValue(field *mapping.StructField, value s
 	}
 	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: SimpleModel'", field.Name())
 }
+
+// Compile time check if VersionedModel implements mapping.Model interface.
+var _ mapping.Model = &VersionedModel{}
+
+// NeuronCollectionName implements mapping.Model interface method.
+// Returns the name of the collection for the 'VersionedModel'.
+func (v *VersionedModel) NeuronCollectionName() string {
+	return "versioned_models"
+}
+
+// IsPrimaryKeyZero implements mapping.Model interface method.
+func (v *VersionedModel) IsPrimaryKeyZero() bool {
+	return v.ID == 0
+}
+
+// GetPrimaryKeyValue implements mapping.Model interface method.
+func (v *VersionedModel) GetPrimaryKeyValue() interface{} {
+	return v.ID
+}
+
+// GetPrimaryKeyStringValue implements mapping.Model interface method.
+func (v *VersionedModel) GetPrimaryKeyStringValue() (string, error) {
+	return strconv.FormatInt(int64(v.ID), 10), nil
+}
+
+// GetPrimaryKeyAddress implements mapping.Model interface method.
+func (v *VersionedModel) GetPrimaryKeyAddress() interface{} {
+	return &v.ID
+}
+
+// GetPrimaryKeyHashableValue implements mapping.Model interface method.
+func (v *VersionedModel) GetPrimaryKeyHashableValue() interface{} {
+	return v.ID
+}
+
+// GetPrimaryKeyZeroValue implements mapping.Model interface method.
+func (v *VersionedModel) GetPrimaryKeyZeroValue() interface{} {
+	return 0
+}
+
+// SetPrimaryKey implements mapping.Model interface method.
+func (v *VersionedModel) SetPrimaryKeyValue(value interface{}) error {
+	if val, ok := value.(int); ok {
+		v.ID = val
+		return nil
+	}
+	// Check alternate types for given field.
+	switch valueType := value.(type) {
+	case int8:
+		v.ID = int(valueType)
+	case int16:
+		v.ID = int(valueType)
+	case int32:
+		v.ID = int(valueType)
+	case int64:
+		v.ID = int(valueType)
+	case uint:
+		v.ID = int(valueType)
+	case uint8:
+		v.ID = int(valueType)
+	case uint16:
+		v.ID = int(valueType)
+	case uint32:
+		v.ID = int(valueType)
+	case uint64:
+		v.ID = int(valueType)
+	case float32:
+		v.ID = int(valueType)
+	case float64:
+		v.ID = int(valueType)
+	default:
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid value: '%T' for the primary field for model: 'VersionedModel'", value)
+	}
+	return nil
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (v *VersionedModel) SetPrimaryKeyStringValue(value string) error {
+	tmp, err := strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	if err != nil {
+		return err
+	}
+	v.ID = int(tmp)
+	return nil
+}
+
+// SetFrom implements FromSetter interface.
+func (v *VersionedModel) SetFrom(model mapping.Model) error {
+	if model == nil {
+		return errors.Wrap(query.ErrInvalidInput, "provided nil model to set from")
+	}
+	from, ok := model.(*VersionedModel)
+	if !ok {
+		return errors.WrapDetf(mapping.ErrModelNotMatch, "provided model doesn't match the input: %T", model)
+	}
+	*v = *from
+	return nil
+}
+
+// Compile time check if VersionedModel implements mapping.Fielder interface.
+var _ mapping.Fielder = &VersionedModel{}
+
+// GetFieldsAddress gets the address of provided 'field'.
+func (v *VersionedModel) GetFieldsAddress(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return &v.ID, nil
+	case 1: // Name
+		return &v.Name, nil
+	case 2: // Version
+		return &v.Version, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: VersionedModel'", field.Name())
+}
+
+// GetFieldZeroValue implements mapping.Fielder interface.s
+func (v *VersionedModel) GetFieldZeroValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return 0, nil
+	case 1: // Name
+		return "", nil
+	case 2: // Version
+		return 0, nil
+	default:
+		return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+}
+
+// IsFieldZero implements mapping.Fielder interface.
+func (v *VersionedModel) IsFieldZero(field *mapping.StructField) (bool, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return v.ID == 0, nil
+	case 1: // Name
+		return v.Name == "", nil
+	case 2: // Version
+		return v.Version == 0, nil
+	}
+	return false, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+}
+
+// SetFieldZeroValue implements mapping.Fielder interface.s
+func (v *VersionedModel) SetFieldZeroValue(field *mapping.StructField) error {
+	switch field.Index[0] {
+	case 0: // ID
+		v.ID = 0
+	case 1: // Name
+		v.Name = ""
+	case 2: // Version
+		v.Version = 0
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field name: '%s'", field.Name())
+	}
+	return nil
+}
+
+// GetHashableFieldValue implements mapping.Fielder interface.
+func (v *VersionedModel) GetHashableFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return v.ID, nil
+	case 1: // Name
+		return v.Name, nil
+	case 2: // Version
+		return v.Version, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: 'VersionedModel'", field.Name())
+}
+
+// GetFieldValue implements mapping.Fielder interface.
+func (v *VersionedModel) GetFieldValue(field *mapping.StructField) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return v.ID, nil
+	case 1: // Name
+		return v.Name, nil
+	case 2: // Version
+		return v.Version, nil
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: VersionedModel'", field.Name())
+}
+
+// SetFieldValue implements mapping.Fielder interface.
+func (v *VersionedModel) SetFieldValue(field *mapping.StructField, value interface{}) (err error) {
+	switch field.Index[0] {
+	case 0: // ID
+		if val, ok := value.(int); ok {
+			v.ID = val
+			return nil
+		}
+
+		switch val := value.(type) {
+		case int8:
+			v.ID = int(val)
+		case int16:
+			v.ID = int(val)
+		case int32:
+			v.ID = int(val)
+		case int64:
+			v.ID = int(val)
+		case uint:
+			v.ID = int(val)
+		case uint8:
+			v.ID = int(val)
+		case uint16:
+			v.ID = int(val)
+		case uint32:
+			v.ID = int(val)
+		case uint64:
+			v.ID = int(val)
+		case float32:
+			v.ID = int(val)
+		case float64:
+			v.ID = int(val)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	case 1: // Name
+		if val, ok := value.(string); ok {
+			v.Name = val
+			return nil
+		}
+
+		// Check alternate types for the Name.
+		if val, ok := value.([]byte); ok {
+			v.Name = string(val)
+			return nil
+		}
+		return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+	case 2: // Version
+		if val, ok := value.(int); ok {
+			v.Version = val
+			return nil
+		}
+
+		switch val := value.(type) {
+		case int8:
+			v.Version = int(val)
+		case int16:
+			v.Version = int(val)
+		case int32:
+			v.Version = int(val)
+		case int64:
+			v.Version = int(val)
+		case uint:
+			v.Version = int(val)
+		case uint8:
+			v.Version = int(val)
+		case uint16:
+			v.Version = int(val)
+		case uint32:
+			v.Version = int(val)
+		case uint64:
+			v.Version = int(val)
+		case float32:
+			v.Version = int(val)
+		case float64:
+			v.Version = int(val)
+		default:
+			return errors.Wrapf(mapping.ErrFieldValue, "provided invalid field type: '%T' for the field: %s", value, field.Name())
+		}
+		return nil
+	default:
+		return errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for the model: 'VersionedModel'", field.Name())
+	}
+}
+
+// SetPrimaryKeyStringValue implements mapping.Model interface method.
+func (v *VersionedModel) ParseFieldsStringValue(field *mapping.StructField, value string) (interface{}, error) {
+	switch field.Index[0] {
+	case 0: // ID
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	case 1: // Name
+		return value, nil
+	case 2: // Version
+		return strconv.ParseInt(value, 10, mapping.IntegerBitSize)
+	}
+	return nil, errors.Wrapf(mapping.ErrInvalidModelField, "provided invalid field: '%s' for given model: VersionedModel'", field.Name())
+}
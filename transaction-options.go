@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+)
+
+// TxOptions are the postgres specific transaction options that extends the query.TxOptions. The options are
+// set in the transaction context using the WithTxOptions function.
+type TxOptions struct {
+	// Deferrable makes the serializable read only transaction wait for a snapshot that is free of the serialization
+	// anomalies. Such transaction could not fail on the serialization failure, which makes it suitable for
+	// the long running consistent reports and exports. It requires the transaction to be read only.
+	Deferrable bool
+	// StatementTimeout is the 'statement_timeout' setting of the transaction. Zero value leaves the session default.
+	StatementTimeout time.Duration
+	// LockTimeout is the 'lock_timeout' setting of the transaction. Zero value leaves the session default.
+	LockTimeout time.Duration
+	// SearchPath is the 'search_path' setting of the transaction. Empty value leaves the session default.
+	SearchPath []string
+	// Timeout is the maximum duration of the transaction after which it is rolled back by the repository.
+	// It overrides the repository TxTimeout option.
+	Timeout time.Duration
+}
+
+// WithTxOptions sets the postgres specific transaction 'options' in the context. The context should be used
+// to begin the transaction i.e. by the database.Begin function.
+func WithTxOptions(ctx context.Context, options TxOptions) context.Context {
+	return context.WithValue(ctx, internal.TxOptionsKey, options)
+}
+
+func txOptionsFromContext(ctx context.Context) (TxOptions, bool) {
+	options, ok := ctx.Value(internal.TxOptionsKey).(TxOptions)
+	return options, ok
+}
+
+// pgxTxOptions gets the pgx transaction options for the neuron transaction 'tx' and the postgres specific 'options'.
+func pgxTxOptions(tx *query.Transaction, options TxOptions) (pgx.TxOptions, error) {
+	txOpts := pgx.TxOptions{}
+	var isolation query.IsolationLevel
+	if tx.Options != nil {
+		isolation = tx.Options.Isolation
+		switch isolation {
+		case query.LevelDefault:
+		case query.LevelSerializable:
+			txOpts.IsoLevel = pgx.Serializable
+		case query.LevelReadCommitted:
+			txOpts.IsoLevel = pgx.ReadCommitted
+		case query.LevelReadUncommitted:
+			txOpts.IsoLevel = pgx.ReadUncommitted
+		case query.LevelRepeatableRead, query.LevelSnapshot:
+			txOpts.IsoLevel = pgx.RepeatableRead
+		default:
+			return txOpts, errors.WrapDetf(query.ErrTxState, "unsupported isolation level: %s", tx.Options.Isolation.String())
+		}
+		if tx.Options.ReadOnly {
+			txOpts.AccessMode = pgx.ReadOnly
+		}
+	}
+	if err := options.validate(); err != nil {
+		return txOpts, err
+	}
+	if options.Deferrable {
+		if txOpts.AccessMode != pgx.ReadOnly {
+			return txOpts, errors.WrapDet(query.ErrTxInvalid, "deferrable transaction must be read only")
+		}
+		// The deferrable mode affects only the serializable transactions.
+		switch isolation {
+		case query.LevelDefault:
+			txOpts.IsoLevel = pgx.Serializable
+		case query.LevelSerializable:
+		default:
+			return txOpts, errors.WrapDetf(query.ErrTxInvalid, "deferrable transaction requires serializable isolation level, but is: %s", isolation)
+		}
+		txOpts.DeferrableMode = pgx.Deferrable
+	}
+	return txOpts, nil
+}
+
+func (t TxOptions) validate() error {
+	if t.StatementTimeout < 0 {
+		return errors.WrapDetf(query.ErrTxInvalid, "negative statement timeout: %s", t.StatementTimeout)
+	}
+	if t.Timeout < 0 {
+		return errors.WrapDetf(query.ErrTxInvalid, "negative transaction timeout: %s", t.Timeout)
+	}
+	if t.LockTimeout < 0 {
+		return errors.WrapDetf(query.ErrTxInvalid, "negative lock timeout: %s", t.LockTimeout)
+	}
+	for _, schema := range t.SearchPath {
+		if schema == "" {
+			return errors.WrapDet(query.ErrTxInvalid, "empty search path schema name")
+		}
+	}
+	return nil
+}
+
+// settingsQuery gets the query with the 'SET LOCAL' statements for the transaction settings. Returns an empty
+// string if there are no settings to apply.
+func settingsQuery(options TxOptions) string {
+	sb := &strings.Builder{}
+	if options.StatementTimeout > 0 {
+		sb.WriteString("SET LOCAL statement_timeout = ")
+		sb.WriteString(strconv.FormatInt(timeoutMilliseconds(options.StatementTimeout), 10))
+		sb.WriteString("; ")
+	}
+	if options.LockTimeout > 0 {
+		sb.WriteString("SET LOCAL lock_timeout = ")
+		sb.WriteString(strconv.FormatInt(timeoutMilliseconds(options.LockTimeout), 10))
+		sb.WriteString("; ")
+	}
+	if len(options.SearchPath) > 0 {
+		sb.WriteString("SET LOCAL search_path TO ")
+		for i, schema := range options.SearchPath {
+			if i != 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(pgx.Identifier{schema}.Sanitize())
+		}
+		sb.WriteString("; ")
+	}
+	return strings.TrimSuffix(sb.String(), " ")
+}
+
+// timeoutMilliseconds gets the timeout in milliseconds. The timeouts shorter than a millisecond are rounded up,
+// as the zero value disables the timeout.
+func timeoutMilliseconds(d time.Duration) int64 {
+	ms := int64(d / time.Millisecond)
+	if d%time.Millisecond != 0 {
+		ms++
+	}
+	return ms
+}
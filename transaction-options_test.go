@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+)
+
+func TestPgxTxOptions(t *testing.T) {
+	t.Run("Deferrable", func(t *testing.T) {
+		tx := &query.Transaction{Options: &query.TxOptions{ReadOnly: true}}
+		txOpts, err := pgxTxOptions(tx, TxOptions{Deferrable: true})
+		require.NoError(t, err)
+		assert.Equal(t, pgx.TxOptions{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly, DeferrableMode: pgx.Deferrable}, txOpts)
+	})
+
+	t.Run("DeferrableReadWrite", func(t *testing.T) {
+		_, err := pgxTxOptions(&query.Transaction{}, TxOptions{Deferrable: true})
+		assert.True(t, errors.Is(err, query.ErrTxInvalid))
+	})
+
+	t.Run("DeferrableReadCommitted", func(t *testing.T) {
+		tx := &query.Transaction{Options: &query.TxOptions{ReadOnly: true, Isolation: query.LevelReadCommitted}}
+		_, err := pgxTxOptions(tx, TxOptions{Deferrable: true})
+		assert.True(t, errors.Is(err, query.ErrTxInvalid))
+	})
+
+	t.Run("NegativeTimeout", func(t *testing.T) {
+		_, err := pgxTxOptions(&query.Transaction{}, TxOptions{LockTimeout: -time.Second})
+		assert.True(t, errors.Is(err, query.ErrTxInvalid))
+	})
+}
+
+func TestSettingsQuery(t *testing.T) {
+	assert.Equal(t, "", settingsQuery(TxOptions{}))
+	settings := settingsQuery(TxOptions{
+		StatementTimeout: 2 * time.Second,
+		LockTimeout:      1500 * time.Microsecond,
+		SearchPath:       []string{"reports", "public"},
+	})
+	assert.Equal(t, `SET LOCAL statement_timeout = 2000; SET LOCAL lock_timeout = 2; SET LOCAL search_path TO "reports", "public";`, settings)
+}
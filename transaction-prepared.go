@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+// maxGIDLength is the maximum length of the prepared transaction identifier.
+const maxGIDLength = 200
+
+// DefaultOrphanedPreparedAge is the default minimum age of the orphaned prepared transaction.
+const DefaultOrphanedPreparedAge = 10 * time.Minute
+
+// PreparedTransaction is the transaction prepared for the two-phase commit.
+type PreparedTransaction struct {
+	// GID is the global identifier of the prepared transaction.
+	GID string
+	// Prepared is the time when the transaction was prepared.
+	Prepared time.Time
+	// Owner is the name of the user that prepared the transaction.
+	Owner string
+	// Database is the name of the database in which the transaction was prepared.
+	Database string
+	// Age is the time elapsed since the transaction was prepared, measured by the database server.
+	Age time.Duration
+	// Orphaned defines if the transaction was prepared by a replica of this repository, i.e. its identifier has
+	// the Postgres.PreparedGIDPrefix, is not known to this instance and is older than the Postgres.OrphanedPreparedAge.
+	// It usually means that the instance that prepared it crashed before the transaction was resolved.
+	Orphaned bool
+}
+
+// Prepare prepares the transaction 'tx' for the two-phase commit with the global identifier 'gid'.
+// The prepared transaction is no longer associated with the transaction ID, nor with the connection. It must be
+// resolved using the CommitPrepared or RollbackPrepared methods, possibly by another repository instance.
+// The transaction must not have any open nested transactions.
+func (p *Postgres) Prepare(ctx context.Context, tx *query.Transaction, gid string) error {
+	if tx == nil {
+		return errors.WrapDet(query.ErrTxInvalid, "scope's transaction is nil")
+	}
+	if err := validateGID(gid); err != nil {
+		return err
+	}
+	t, timedOut := p.activeTransaction(tx.ID)
+	if timedOut {
+		p.clearTransaction(tx.ID)
+		return errors.WrapDetf(query.ErrTxDone, "transaction: %s exceeded its deadline and was rolled back", tx.ID)
+	}
+	if t == nil {
+		log.Errorf("Transaction: '%s' no mapped SQL transaction found", tx.ID)
+		return errors.WrapDet(query.ErrTxInvalid, "no mapped sql transaction found for the scope")
+	}
+	p.lock.RLock()
+	savepoints := t.savepoints
+	p.lock.RUnlock()
+	if savepoints > 0 {
+		return errors.WrapDetf(query.ErrTxState, "transaction: %s has open nested transactions", tx.ID)
+	}
+	// Once the transaction is prepared, or failed to do so, it is no longer associated with the transaction ID.
+	p.clearTransaction(tx.ID)
+
+	if log.Level().IsAllowed(log.LevelDebug3) {
+		log.Debug3f("[POSTGRES:%s][TX:%s] PREPARE TRANSACTION '%s';", p.id, tx.ID, gid)
+	}
+	_, err := t.tx.Exec(ctx, "PREPARE TRANSACTION "+quoteLiteral(gid))
+	// The session is no longer in the transaction, thus the rollback only releases the connection.
+	if er := t.tx.Rollback(ctx); er != nil {
+		log.Debugf("[POSTGRES:%s][TX:%s] releasing prepared transaction connection failed: %v", p.id, tx.ID, er)
+	}
+	if err != nil {
+		return errors.WrapDetf(p.neuronError(err), "prepare transaction: %s failed: %v", tx.ID, err)
+	}
+	p.setPrepared(gid, tx.ID)
+	return nil
+}
+
+// CommitPrepared commits the prepared transaction with the global identifier 'gid'.
+func (p *Postgres) CommitPrepared(ctx context.Context, gid string) error {
+	return p.finishPrepared(ctx, "COMMIT PREPARED ", gid)
+}
+
+// RollbackPrepared rolls back the prepared transaction with the global identifier 'gid'.
+func (p *Postgres) RollbackPrepared(ctx context.Context, gid string) error {
+	return p.finishPrepared(ctx, "ROLLBACK PREPARED ", gid)
+}
+
+// PreparedTransactions lists the transactions prepared in the current database. The transactions of this repository
+// that were not prepared by this instance and are older than the Postgres.OrphanedPreparedAge are marked as orphaned.
+func (p *Postgres) PreparedTransactions(ctx context.Context) ([]*PreparedTransaction, error) {
+	if p.ConnPool == nil {
+		return nil, errors.Wrap(query.ErrTxState, "no connection established")
+	}
+	rows, err := p.ConnPool.Query(ctx, "SELECT gid, prepared, EXTRACT(EPOCH FROM now() - prepared), owner, database FROM pg_prepared_xacts WHERE database = current_database() ORDER BY prepared")
+	if err != nil {
+		return nil, errors.WrapDetf(p.neuronError(err), "listing prepared transactions failed: %v", err)
+	}
+	defer rows.Close()
+
+	var prepared []*PreparedTransaction
+	for rows.Next() {
+		pt := &PreparedTransaction{}
+		var age float64
+		if err = rows.Scan(&pt.GID, &pt.Prepared, &age, &pt.Owner, &pt.Database); err != nil {
+			return nil, errors.WrapDetf(p.neuronError(err), "listing prepared transactions failed: %v", err)
+		}
+		pt.Age = time.Duration(age * float64(time.Second))
+		pt.Orphaned = p.isOrphaned(pt.GID, pt.Age)
+		prepared = append(prepared, pt)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.WrapDetf(p.neuronError(err), "listing prepared transactions failed: %v", err)
+	}
+	return prepared, nil
+}
+
+// ResolveOrphaned resolves the orphaned prepared transactions of the current database. The 'commit' function
+// decides if given transaction should be committed or rolled back. It must consult the durable log of the two-phase
+// commit coordinator and return true only if the commit decision of the global transaction was recorded there,
+// as some of its participants might already be committed. Returns the resolved transactions.
+func (p *Postgres) ResolveOrphaned(ctx context.Context, commit func(pt *PreparedTransaction) bool) ([]*PreparedTransaction, error) {
+	prepared, err := p.PreparedTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var resolved []*PreparedTransaction
+	for _, pt := range prepared {
+		if !pt.Orphaned {
+			continue
+		}
+		if commit(pt) {
+			err = p.CommitPrepared(ctx, pt.GID)
+		} else {
+			err = p.RollbackPrepared(ctx, pt.GID)
+		}
+		if err != nil {
+			return resolved, err
+		}
+		log.Infof("[POSTGRES:%s] Resolved orphaned prepared transaction: '%s' prepared at: %s", p.id, pt.GID, pt.Prepared)
+		resolved = append(resolved, pt)
+	}
+	return resolved, nil
+}
+
+func (p *Postgres) finishPrepared(ctx context.Context, statement, gid string) error {
+	if err := validateGID(gid); err != nil {
+		return err
+	}
+	if p.ConnPool == nil {
+		return errors.Wrap(query.ErrTxState, "no connection established")
+	}
+	if log.Level().IsAllowed(log.LevelDebug3) {
+		log.Debug3f("[POSTGRES:%s] %s'%s';", p.id, statement, gid)
+	}
+	if _, err := p.ConnPool.Exec(ctx, statement+quoteLiteral(gid)); err != nil {
+		return errors.WrapDetf(p.neuronError(err), "%sfailed for transaction: '%s': %v", strings.ToLower(statement), gid, err)
+	}
+	p.clearPrepared(gid)
+	return nil
+}
+
+func (p *Postgres) setPrepared(gid string, id uuid.UUID) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.prepared[gid] = id
+}
+
+func (p *Postgres) clearPrepared(gid string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.prepared, gid)
+}
+
+func (p *Postgres) isPrepared(gid string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	_, ok := p.prepared[gid]
+	return ok
+}
+
+// isOrphaned checks if the prepared transaction with the global identifier 'gid' and given 'age' belongs to this
+// repository, but is not known to this instance and is older than the Postgres.OrphanedPreparedAge.
+func (p *Postgres) isOrphaned(gid string, age time.Duration) bool {
+	if p.PreparedGIDPrefix == "" || !strings.HasPrefix(gid, p.PreparedGIDPrefix) {
+		return false
+	}
+	if age < p.OrphanedPreparedAge {
+		return false
+	}
+	return !p.isPrepared(gid)
+}
+
+func validateGID(gid string) error {
+	if gid == "" {
+		return errors.WrapDet(query.ErrTxInvalid, "empty prepared transaction identifier")
+	}
+	if len(gid) >= maxGIDLength {
+		return errors.WrapDetf(query.ErrTxInvalid, "prepared transaction identifier must be shorter than %d bytes", maxGIDLength)
+	}
+	return nil
+}
+
+// quoteLiteral quotes the string 'value' as the SQL literal.
+func quoteLiteral(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+)
+
+// execTx is the pgx.Tx that records the executed statements and the rollbacks.
+type execTx struct {
+	pgx.Tx
+	statements []string
+	rolledBack bool
+}
+
+func (e *execTx) Exec(_ context.Context, sql string, _ ...interface{}) (pgconn.CommandTag, error) {
+	e.statements = append(e.statements, sql)
+	return nil, nil
+}
+
+func (e *execTx) Rollback(context.Context) error {
+	e.rolledBack = true
+	return nil
+}
+
+func TestPrepare(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid", func(t *testing.T) {
+		p := newPostgres()
+		tx := &query.Transaction{ID: uuid.New()}
+		pgxTx := &execTx{}
+		p.setTransaction(tx.ID, &transaction{tx: pgxTx})
+
+		require.NoError(t, p.Prepare(ctx, tx, "order's-1"))
+		assert.Equal(t, []string{"PREPARE TRANSACTION 'order''s-1'"}, pgxTx.statements)
+		assert.True(t, pgxTx.rolledBack)
+
+		// The prepared transaction is no longer in the transactions map.
+		assert.Equal(t, 0, p.OpenTransactions())
+		assert.True(t, p.isPrepared("order's-1"))
+	})
+
+	t.Run("Savepoints", func(t *testing.T) {
+		p := newPostgres()
+		tx := &query.Transaction{ID: uuid.New()}
+		p.setTransaction(tx.ID, &transaction{tx: &execTx{}, savepoints: 1})
+
+		err := p.Prepare(ctx, tx, "gid")
+		assert.True(t, errors.Is(err, query.ErrTxState))
+		assert.Equal(t, 1, p.OpenTransactions())
+	})
+
+	t.Run("InvalidGID", func(t *testing.T) {
+		p := newPostgres()
+		tx := &query.Transaction{ID: uuid.New()}
+		p.setTransaction(tx.ID, &transaction{tx: &execTx{}})
+
+		err := p.Prepare(ctx, tx, "")
+		assert.True(t, errors.Is(err, query.ErrTxInvalid))
+	})
+}
+
+func TestIsOrphaned(t *testing.T) {
+	p := newPostgres()
+	p.setPrepared("repo-known", uuid.New())
+
+	// No prefix disables the orphaned transactions detection.
+	assert.False(t, p.isOrphaned("repo-unknown", time.Hour))
+
+	p.PreparedGIDPrefix = "repo-"
+	assert.True(t, p.isOrphaned("repo-unknown", time.Hour))
+	// The transactions prepared by this instance, the ones of other repositories and the recent ones are not orphaned.
+	assert.False(t, p.isOrphaned("repo-known", time.Hour))
+	assert.False(t, p.isOrphaned("other-unknown", time.Hour))
+	assert.False(t, p.isOrphaned("repo-unknown", time.Second))
+}
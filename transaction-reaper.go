@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+const (
+	// DefaultTxReaperInterval is the default interval of checking for the expired transactions.
+	DefaultTxReaperInterval = 10 * time.Second
+	// txRollbackTimeout is the timeout of rolling back the expired or outstanding transaction.
+	txRollbackTimeout = 5 * time.Second
+	// txTimedOutRetention is the duration for which the IDs of the timed out transactions are kept.
+	txTimedOutRetention = time.Hour
+)
+
+// OpenTransactions gets the number of currently open transactions of the repository.
+func (p *Postgres) OpenTransactions() int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return len(p.transactions)
+}
+
+// expired checks if the transaction deadline is exceeded at the time 'now'.
+func (t *transaction) expired(now time.Time) bool {
+	return !t.deadline.IsZero() && now.After(t.deadline)
+}
+
+// transactionDeadline gets the deadline of the transaction started at 'started'. The timeout defined
+// in the transaction 'options' takes precedence over the repository TxTimeout.
+func (p *Postgres) transactionDeadline(started time.Time, options TxOptions) time.Time {
+	timeout := p.TxTimeout
+	if options.Timeout > 0 {
+		timeout = options.Timeout
+	}
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return started.Add(timeout)
+}
+
+// startReaper starts the background routine that rolls back the expired transactions. The reaper is started only if
+// the repository TxTimeout is set.
+func (p *Postgres) startReaper() {
+	interval := p.TxReaperInterval
+	if interval <= 0 || p.TxTimeout <= 0 {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.reaperDone != nil {
+		return
+	}
+	p.reaperDone = make(chan struct{})
+	go p.reapTransactions(interval, p.reaperDone)
+}
+
+// stopReaper stops the expired transactions reaper.
+func (p *Postgres) stopReaper() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.reaperDone != nil {
+		close(p.reaperDone)
+		p.reaperDone = nil
+	}
+}
+
+func (p *Postgres) reapTransactions(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case now := <-ticker.C:
+			p.expireTransactions(now)
+		}
+	}
+}
+
+// expireTransactions rolls back and removes all the transactions expired at the time 'now', so that their connections
+// are released even if these were leaked by their owners. The IDs of the timed out transactions are kept, thus their
+// owners get the query.ErrTxDone error on the next use. The IDs kept longer than txTimedOutRetention are forgotten.
+// Returns the number of rolled back transactions.
+func (p *Postgres) expireTransactions(now time.Time) int {
+	p.lock.Lock()
+	expired := map[uuid.UUID]*transaction{}
+	for id, t := range p.transactions {
+		if !t.expired(now) {
+			continue
+		}
+		delete(p.transactions, id)
+		p.timedOut[id] = now
+		expired[id] = t
+	}
+	for id, timedOut := range p.timedOut {
+		if now.Sub(timedOut) > txTimedOutRetention {
+			delete(p.timedOut, id)
+		}
+	}
+	p.lock.Unlock()
+
+	for id, t := range expired {
+		log.Errorf("[POSTGRES:%s][TX:%s] transaction expired after: %s - rolling back", p.id, id, now.Sub(t.started))
+		p.rollbackTransaction(id, t)
+	}
+	return len(expired)
+}
+
+// activeTransaction gets the transaction with given 'id'. If the transaction exceeded its deadline, it is rolled back
+// and removed on the calling goroutine. The timedOut flag is returned for the transactions rolled back by
+// the repository, until these are cleared by their owners.
+func (p *Postgres) activeTransaction(id uuid.UUID) (t *transaction, timedOut bool) {
+	now := time.Now()
+	p.lock.Lock()
+	if _, timedOut = p.timedOut[id]; timedOut {
+		p.lock.Unlock()
+		return nil, true
+	}
+	t, ok := p.transactions[id]
+	if !ok || !t.expired(now) {
+		p.lock.Unlock()
+		return t, false
+	}
+	delete(p.transactions, id)
+	p.timedOut[id] = now
+	p.lock.Unlock()
+
+	log.Debugf("[POSTGRES:%s][TX:%s] rolling back the expired transaction", p.id, id)
+	p.rollbackTransaction(id, t)
+	return nil, true
+}
+
+// rollbackAll rolls back and removes all the outstanding transactions. Returns the number of rolled back transactions.
+func (p *Postgres) rollbackAll() int {
+	p.lock.Lock()
+	outstanding := p.transactions
+	p.transactions = map[uuid.UUID]*transaction{}
+	p.timedOut = map[uuid.UUID]time.Time{}
+	p.lock.Unlock()
+
+	for id, t := range outstanding {
+		log.Errorf("[POSTGRES:%s][TX:%s] transaction started at: %s not finished on close - rolling back", p.id, id, t.started)
+		p.rollbackTransaction(id, t)
+	}
+	return len(outstanding)
+}
+
+func (p *Postgres) rollbackTransaction(id uuid.UUID, t *transaction) {
+	ctx, cancel := context.WithTimeout(context.Background(), txRollbackTimeout)
+	defer cancel()
+	if err := t.tx.Rollback(ctx); err != nil {
+		log.Errorf("[POSTGRES:%s][TX:%s] rolling back transaction failed: %v", p.id, id, err)
+	}
+}
+
+// timedOutConnection is the connection of the transaction rolled back by the repository. All its queries fail with
+// the pgx.ErrTxClosed error.
+type timedOutConnection struct{}
+
+// Exec implements internal.Connection interface.
+func (timedOutConnection) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return nil, pgx.ErrTxClosed
+}
+
+// Query implements internal.Connection interface.
+func (timedOutConnection) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, pgx.ErrTxClosed
+}
+
+// QueryRow implements internal.Connection interface.
+func (timedOutConnection) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return timedOutBatchResults{}
+}
+
+// SendBatch implements internal.Connection interface.
+func (timedOutConnection) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults {
+	return timedOutBatchResults{}
+}
+
+// CopyFrom implements internal.Connection interface.
+func (timedOutConnection) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
+	return 0, pgx.ErrTxClosed
+}
+
+// timedOutBatchResults are the results of the timed out connection batch and row queries.
+type timedOutBatchResults struct{}
+
+// Exec implements pgx.BatchResults interface.
+func (timedOutBatchResults) Exec() (pgconn.CommandTag, error) {
+	return nil, pgx.ErrTxClosed
+}
+
+// Query implements pgx.BatchResults interface.
+func (timedOutBatchResults) Query() (pgx.Rows, error) {
+	return nil, pgx.ErrTxClosed
+}
+
+// QueryRow implements pgx.BatchResults interface.
+func (r timedOutBatchResults) QueryRow() pgx.Row {
+	return r
+}
+
+// Close implements pgx.BatchResults interface.
+func (timedOutBatchResults) Close() error {
+	return nil
+}
+
+// Scan implements pgx.Row interface.
+func (timedOutBatchResults) Scan(...interface{}) error {
+	return pgx.ErrTxClosed
+}
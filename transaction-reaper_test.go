@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+)
+
+// rollbackTx is the pgx.Tx that only records the rollbacks.
+type rollbackTx struct {
+	pgx.Tx
+	rolledBack bool
+}
+
+func (r *rollbackTx) Rollback(context.Context) error {
+	r.rolledBack = true
+	return nil
+}
+
+func TestExpireTransactions(t *testing.T) {
+	p := newPostgres()
+	p.TxTimeout = time.Minute
+
+	now := time.Now()
+	expired, active, noDeadline := &rollbackTx{}, &rollbackTx{}, &rollbackTx{}
+	expiredID := uuid.New()
+	p.setTransaction(expiredID, &transaction{tx: expired, started: now.Add(-2 * time.Minute), deadline: p.transactionDeadline(now.Add(-2*time.Minute), TxOptions{})})
+	p.setTransaction(uuid.New(), &transaction{tx: active, started: now, deadline: p.transactionDeadline(now, TxOptions{})})
+	p.setTransaction(uuid.New(), &transaction{tx: noDeadline, started: now.Add(-time.Hour)})
+	require.Equal(t, 3, p.OpenTransactions())
+
+	// The leaked expired transaction is rolled back by the reaper, without being used by its owner.
+	assert.Equal(t, 1, p.expireTransactions(now))
+	assert.True(t, expired.rolledBack)
+	assert.Equal(t, 2, p.OpenTransactions())
+	assert.Equal(t, 0, p.expireTransactions(now))
+
+	// The queries of the timed out transaction fail without touching the rolled back transaction.
+	s := query.NewScope(nil)
+	s.Transaction = &query.Transaction{ID: expiredID}
+	_, err := p.connection(s).Exec(context.Background(), "SELECT 1")
+	assert.Equal(t, pgx.ErrTxClosed, err)
+	assert.True(t, errors.Is(p.neuronError(err), query.ErrTxDone))
+
+	err = p.Begin(context.Background(), s.Transaction)
+	assert.True(t, errors.Is(err, query.ErrTxDone))
+	err = p.Commit(context.Background(), s.Transaction)
+	assert.True(t, errors.Is(err, query.ErrTxDone))
+	// Once finished by the owner the timed out transaction is forgotten.
+	err = p.Commit(context.Background(), s.Transaction)
+	assert.True(t, errors.Is(err, query.ErrTxInvalid))
+
+	// Close rolls back all the outstanding transactions.
+	require.NoError(t, p.Close(context.Background()))
+	assert.True(t, active.rolledBack)
+	assert.True(t, noDeadline.rolledBack)
+	assert.Equal(t, 0, p.OpenTransactions())
+}
+
+func TestExpireTransactionsRetention(t *testing.T) {
+	p := newPostgres()
+	now := time.Now()
+	id := uuid.New()
+	p.setTransaction(id, &transaction{tx: &rollbackTx{}, started: now, deadline: now.Add(time.Second)})
+
+	require.Equal(t, 1, p.expireTransactions(now.Add(time.Minute)))
+	_, timedOut := p.activeTransaction(id)
+	assert.True(t, timedOut)
+
+	// The owner that never finished the timed out transaction is forgotten after the retention.
+	p.expireTransactions(now.Add(txTimedOutRetention + 2*time.Minute))
+	_, timedOut = p.activeTransaction(id)
+	assert.False(t, timedOut)
+}
+
+func TestExpiredRollback(t *testing.T) {
+	p := newPostgres()
+	started := time.Now().Add(-time.Minute)
+	expired := &rollbackTx{}
+	tx := &query.Transaction{ID: uuid.New()}
+	// The transaction deadline is checked on use, even if the reaper is not running.
+	p.setTransaction(tx.ID, &transaction{tx: expired, started: started, deadline: p.transactionDeadline(started, TxOptions{Timeout: time.Second})})
+
+	require.NoError(t, p.Rollback(context.Background(), tx))
+	assert.True(t, expired.rolledBack)
+	assert.Equal(t, 0, p.OpenTransactions())
+	_, timedOut := p.activeTransaction(tx.ID)
+	assert.False(t, timedOut)
+}
+
+func TestStartReaper(t *testing.T) {
+	p := newPostgres()
+	// The reaper is not started without the transaction timeout.
+	p.startReaper()
+	assert.Nil(t, p.reaperDone)
+
+	p.TxTimeout = time.Minute
+	p.startReaper()
+	assert.NotNil(t, p.reaperDone)
+	p.stopReaper()
+	assert.Nil(t, p.reaperDone)
+}
+
+func TestTransactionDeadline(t *testing.T) {
+	p := newPostgres()
+	started := time.Now()
+
+	assert.True(t, p.transactionDeadline(started, TxOptions{}).IsZero())
+	assert.Equal(t, started.Add(time.Second), p.transactionDeadline(started, TxOptions{Timeout: time.Second}))
+
+	p.TxTimeout = time.Minute
+	assert.Equal(t, started.Add(time.Minute), p.transactionDeadline(started, TxOptions{}))
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/neuronlabs/neuron/database"
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+// RetryOptions defines how the RunInTransaction function retries the failed transactions.
+type RetryOptions struct {
+	// MaxRetries is the maximum number of the transaction retries. Zero disables the retries.
+	MaxRetries int
+	// Backoff is the wait duration before the first retry. It is doubled on each subsequent retry.
+	Backoff time.Duration
+	// MaxBackoff is the maximum wait duration between the retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryOptions are the default transaction retry options of the repository.
+var DefaultRetryOptions = RetryOptions{
+	MaxRetries: 5,
+	Backoff:    10 * time.Millisecond,
+	MaxBackoff: time.Second,
+}
+
+// IsRetryable checks if the transaction failed with the 'err' could be successfully retried.
+// These are the serialization failures and the detected deadlocks.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlockDetected)
+}
+
+// RunInTransaction runs the 'txFunc' within a new transaction of the 'db'. If the function returns no error
+// the transaction is committed, otherwise it is rolled back. If the transaction failed on the serialization
+// failure or a deadlock, the whole transaction is started again after the backoff defined by the TxRetry option.
+// The 'txFunc' should then have no side effects other than the queries done within the transaction.
+// If the 'db' is already a transaction, the 'txFunc' is executed only once within it, as the outer transaction
+// could not be retried.
+func (p *Postgres) RunInTransaction(ctx context.Context, db database.DB, options *query.TxOptions, txFunc database.TxFunc) error {
+	if tx, ok := db.(*database.Tx); ok {
+		return txFunc(tx)
+	}
+	return p.retry(ctx, func() error {
+		return database.RunInTransaction(ctx, db, options, txFunc)
+	})
+}
+
+// retry executes the 'fn' until it succeeds, returns an error that is not retryable or the number of the retries
+// exceeds the TxRetry.MaxRetries.
+func (p *Postgres) retry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !IsRetryable(err) || attempt >= p.TxRetry.MaxRetries {
+			return err
+		}
+		backoff := p.TxRetry.backoff(attempt)
+		log.Debugf("[POSTGRES:%s] Transaction failed: %v. Retrying in: %s", p.id, err, backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.WrapDetf(err, "transaction retry canceled: %v", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff gets the jittered wait duration before the retry with given 'attempt' number.
+func (r RetryOptions) backoff(attempt int) time.Duration {
+	backoff := r.Backoff
+	for i := 0; i < attempt && (r.MaxBackoff <= 0 || backoff < r.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+		backoff = r.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	// The jitter spreads the retries of the concurrently failed transactions.
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/query"
+)
+
+func TestRetry(t *testing.T) {
+	p := newPostgres()
+	p.TxRetry = RetryOptions{MaxRetries: 2, Backoff: time.Microsecond, MaxBackoff: time.Millisecond}
+	ctx := context.Background()
+
+	t.Run("Retryable", func(t *testing.T) {
+		var attempts int
+		err := p.retry(ctx, func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.WrapDet(p.neuronError(&pgconn.PgError{Code: "40001"}), "commit failed")
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("MaxRetries", func(t *testing.T) {
+		var attempts int
+		err := p.retry(ctx, func() error {
+			attempts++
+			return p.neuronError(&pgconn.PgError{Code: "40P01"})
+		})
+		assert.True(t, errors.Is(err, ErrDeadlockDetected))
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		var attempts int
+		err := p.retry(ctx, func() error {
+			attempts++
+			return p.neuronError(&pgconn.PgError{Code: "23505"})
+		})
+		assert.True(t, errors.Is(err, query.ErrViolationUnique))
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	r := RetryOptions{Backoff: 10 * time.Millisecond, MaxBackoff: 40 * time.Millisecond}
+	for attempt, expected := range []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond} {
+		backoff := r.backoff(attempt)
+		assert.True(t, backoff >= expected/2 && backoff <= expected, "attempt: %d backoff: %s", attempt, backoff)
+	}
+}
+
+func TestTransactionErrorMapping(t *testing.T) {
+	for code, expected := range map[string]error{
+		"40001": ErrSerializationFailure,
+		"40P01": ErrDeadlockDetected,
+		"55P03": ErrLockNotAvailable,
+		"40002": query.ErrTxState,
+	} {
+		mapped, ok := Get(&pgconn.PgError{Code: code})
+		require.True(t, ok, code)
+		assert.Equal(t, expected, mapped, code)
+	}
+	// The retryable errors are still the transaction state errors.
+	assert.True(t, errors.Is(ErrSerializationFailure, query.ErrTxState))
+}
@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
@@ -16,32 +18,36 @@ import (
 // compile time check for the repository.Transactioner interface.
 var _ repository.Transactioner = &Postgres{}
 
-// Begin starts a transaction for the given scope.
+// transaction is the postgres transaction stored for the neuron transaction ID.
+type transaction struct {
+	// tx is the root postgres transaction.
+	tx pgx.Tx
+	// savepoints is the number of currently open nested transactions.
+	savepoints int
+	// started is the time when the transaction was started.
+	started time.Time
+	// deadline is the time after which the transaction is rolled back by the repository. Zero value means no deadline.
+	deadline time.Time
+}
+
+// Begin starts a transaction for the given scope. If the transaction with given ID was already started, a nested
+// transaction is started by setting a savepoint. The nested transaction could then be committed or rolled back
+// without affecting the outer transaction.
+// The postgres specific options set by the WithTxOptions function in the context 'ctx' are applied only to
+// the root transaction.
 // Implements Begin method of the query.Transactioner interface.
 func (p *Postgres) Begin(ctx context.Context, tx *query.Transaction) error {
-	if _, ok := p.checkTransaction(tx.ID); ok {
-		return nil
+	t, timedOut := p.activeTransaction(tx.ID)
+	if timedOut {
+		return errors.WrapDetf(query.ErrTxDone, "transaction: %s exceeded its deadline and was rolled back", tx.ID)
 	}
-	var isolation pgx.TxIsoLevel
-	txOpts := pgx.TxOptions{IsoLevel: isolation}
-	if tx.Options != nil {
-		switch tx.Options.Isolation {
-		case query.LevelDefault:
-		case query.LevelSerializable:
-			isolation = pgx.Serializable
-		case query.LevelReadCommitted:
-			isolation = pgx.ReadCommitted
-		case query.LevelReadUncommitted:
-			isolation = pgx.ReadUncommitted
-		case query.LevelRepeatableRead, query.LevelSnapshot:
-			isolation = pgx.RepeatableRead
-		default:
-			return errors.WrapDetf(query.ErrTxState, "unsupported isolation level: %s", tx.Options.Isolation.String())
-		}
-		txOpts.IsoLevel = isolation
-		if tx.Options.ReadOnly {
-			txOpts.AccessMode = pgx.ReadOnly
-		}
+	if t != nil {
+		return p.beginSavepoint(ctx, tx, t)
+	}
+	options, _ := txOptionsFromContext(ctx)
+	txOpts, err := pgxTxOptions(tx, options)
+	if err != nil {
+		return err
 	}
 
 	pgxTx, err := p.ConnPool.BeginTx(ctx, txOpts)
@@ -51,7 +57,19 @@ func (p *Postgres) Begin(ctx context.Context, tx *query.Transaction) error {
 	if log.Level().IsAllowed(log.LevelDebug3) {
 		log.Debug3f("[POSTGRES:%s][TX:%s] BEGIN;", p.id, tx.ID)
 	}
-	p.setTransaction(tx.ID, pgxTx)
+	if settings := settingsQuery(options); settings != "" {
+		if log.Level().IsAllowed(log.LevelDebug3) {
+			log.Debug3f("[POSTGRES:%s][TX:%s] %s", p.id, tx.ID, settings)
+		}
+		if _, err = pgxTx.Exec(ctx, settings); err != nil {
+			if er := pgxTx.Rollback(ctx); er != nil {
+				log.Errorf("Rolling back transaction: '%s' failed: %v", tx.ID, er)
+			}
+			return errors.WrapDetf(p.neuronError(err), "setting transaction: %s options failed: %v", tx.ID, err)
+		}
+	}
+	started := time.Now()
+	p.setTransaction(tx.ID, &transaction{tx: pgxTx, started: started, deadline: p.transactionDeadline(started, options)})
 	return nil
 }
 
@@ -62,14 +80,21 @@ func (p *Postgres) Commit(ctx context.Context, tx *query.Transaction) error {
 		return errors.WrapDet(query.ErrTxInvalid, "scope's transaction is nil")
 	}
 
-	pgxTx, ok := p.checkTransaction(tx.ID)
-	if !ok {
+	t, timedOut := p.activeTransaction(tx.ID)
+	if timedOut {
+		p.clearTransaction(tx.ID)
+		return errors.WrapDetf(query.ErrTxDone, "transaction: %s exceeded its deadline and was rolled back", tx.ID)
+	}
+	if t == nil {
 		log.Errorf("Transaction: '%s' no mapped SQL transaction found", tx.ID)
 		return errors.WrapDet(query.ErrTxInvalid, "no mapped sql transaction found for the scope")
 	}
+	if savepoint, ok := p.popSavepoint(t); ok {
+		return p.releaseSavepoint(ctx, tx, t, savepoint)
+	}
 	defer p.clearTransaction(tx.ID)
 	for {
-		err := pgxTx.Commit(ctx)
+		err := t.tx.Commit(ctx)
 		if err == nil {
 			break
 		}
@@ -86,15 +111,23 @@ func (p *Postgres) Rollback(ctx context.Context, tx *query.Transaction) error {
 	if tx == nil {
 		return errors.WrapDet(query.ErrTxInvalid, "scope's transaction is nil")
 	}
-	pgxTx, ok := p.checkTransaction(tx.ID)
-	if !ok {
+	t, timedOut := p.activeTransaction(tx.ID)
+	// The timed out transaction is already rolled back.
+	if timedOut {
+		p.clearTransaction(tx.ID)
+		return nil
+	}
+	if t == nil {
 		log.Errorf("Transaction: '%s' no mapped SQL transaction found", tx.ID)
 		return errors.WrapDet(query.ErrTxInvalid, "no mapped sql transaction found for the scope")
 	}
+	if savepoint, ok := p.popSavepoint(t); ok {
+		return p.rollbackSavepoint(ctx, tx, t, savepoint)
+	}
 	defer p.clearTransaction(tx.ID)
 
 	for {
-		err := pgxTx.Rollback(ctx)
+		err := t.tx.Rollback(ctx)
 		if err == nil {
 			break
 		}
@@ -105,3 +138,63 @@ func (p *Postgres) Rollback(ctx context.Context, tx *query.Transaction) error {
 	}
 	return nil
 }
+
+// beginSavepoint starts the nested transaction within the transaction 't' by setting a new savepoint.
+func (p *Postgres) beginSavepoint(ctx context.Context, tx *query.Transaction, t *transaction) error {
+	p.lock.Lock()
+	t.savepoints++
+	savepoint := savepointName(t.savepoints)
+	p.lock.Unlock()
+
+	if log.Level().IsAllowed(log.LevelDebug3) {
+		log.Debug3f("[POSTGRES:%s][TX:%s] SAVEPOINT %s;", p.id, tx.ID, savepoint)
+	}
+	if _, err := t.tx.Exec(ctx, "SAVEPOINT "+savepoint); err != nil {
+		p.popSavepoint(t)
+		return errors.WrapDetf(p.neuronError(err), "begin nested transaction: %s failed: %v", tx.ID, err)
+	}
+	return nil
+}
+
+// releaseSavepoint commits the nested transaction by releasing its 'savepoint'.
+func (p *Postgres) releaseSavepoint(ctx context.Context, tx *query.Transaction, t *transaction, savepoint string) error {
+	if log.Level().IsAllowed(log.LevelDebug3) {
+		log.Debug3f("[POSTGRES:%s][TX:%s] RELEASE SAVEPOINT %s;", p.id, tx.ID, savepoint)
+	}
+	if _, err := t.tx.Exec(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return errors.WrapDetf(p.neuronError(err), "commit nested transaction: %s failed: %v", tx.ID, err)
+	}
+	return nil
+}
+
+// rollbackSavepoint rolls back the nested transaction to its 'savepoint' and releases it. The outer transaction
+// remains usable.
+func (p *Postgres) rollbackSavepoint(ctx context.Context, tx *query.Transaction, t *transaction, savepoint string) error {
+	if log.Level().IsAllowed(log.LevelDebug3) {
+		log.Debug3f("[POSTGRES:%s][TX:%s] ROLLBACK TO SAVEPOINT %s;", p.id, tx.ID, savepoint)
+	}
+	if _, err := t.tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); err != nil {
+		return errors.WrapDetf(p.neuronError(err), "rollback nested transaction: %s failed: %v", tx.ID, err)
+	}
+	if _, err := t.tx.Exec(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return errors.WrapDetf(p.neuronError(err), "rollback nested transaction: %s failed: %v", tx.ID, err)
+	}
+	return nil
+}
+
+// popSavepoint gets the name of the most recent savepoint of the transaction 't' and removes it from the transaction.
+// Returns false if there is no nested transaction.
+func (p *Postgres) popSavepoint(t *transaction) (string, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if t.savepoints == 0 {
+		return "", false
+	}
+	savepoint := savepointName(t.savepoints)
+	t.savepoints--
+	return savepoint, true
+}
+
+func savepointName(level int) string {
+	return "neuron_sp_" + strconv.Itoa(level)
+}
@@ -5,6 +5,7 @@ package postgres
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -77,4 +78,64 @@ func TestTransactions(t *testing.T) {
 		require.Error(t, err)
 		assert.True(t, errors.Is(err, query.ErrNoResult))
 	})
+
+	t.Run("NestedRollback", func(t *testing.T) {
+		tx := db.Begin(ctx, nil)
+
+		outer := &tests.SimpleModel{Attr: "Outer"}
+		err = tx.Query(mStruct, outer).Insert()
+		require.NoError(t, err)
+
+		// Begin the nested transaction with the same transaction ID.
+		err = p.Begin(ctx, tx.Transaction)
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.transactions[tx.Transaction.ID].savepoints)
+
+		inner := &tests.SimpleModel{Attr: "Inner"}
+		err = tx.Query(mStruct, inner).Insert()
+		require.NoError(t, err)
+
+		err = p.Rollback(ctx, tx.Transaction)
+		require.NoError(t, err)
+
+		_, ok := p.transactions[tx.Transaction.ID]
+		require.True(t, ok)
+		assert.Equal(t, 0, p.transactions[tx.Transaction.ID].savepoints)
+
+		err = tx.Commit()
+		require.NoError(t, err)
+
+		_, err = db.Query(mStruct).Where("id =", outer.ID).Get()
+		require.NoError(t, err)
+
+		_, err = db.Query(mStruct).Where("id =", inner.ID).Get()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, query.ErrNoResult))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		p.TxTimeout = time.Minute
+		defer func() {
+			p.TxTimeout = 0
+		}()
+
+		// The transaction is leaked by its owner with an uncommitted insert.
+		tx := db.Begin(ctx, nil)
+		model := &tests.SimpleModel{Attr: "Leaked"}
+		require.NoError(t, tx.Query(mStruct, model).Insert())
+		open := p.OpenTransactions()
+		acquired := p.ConnPool.Stat().AcquiredConns()
+
+		// The reaper rolls back the expired transaction and releases its connection.
+		assert.Equal(t, 1, p.expireTransactions(time.Now().Add(2*time.Minute)))
+		assert.Equal(t, open-1, p.OpenTransactions())
+		assert.Equal(t, acquired-1, p.ConnPool.Stat().AcquiredConns())
+
+		_, err = db.Query(mStruct).Where("id =", model.ID).Get()
+		assert.True(t, errors.Is(err, query.ErrNoResult))
+
+		// The owner's further use of the transaction fails.
+		err = tx.Commit()
+		assert.True(t, errors.Is(err, query.ErrTxDone))
+	})
 }
@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
+)
+
+// DefaultBulkUpdateThreshold is the default number of models with the same fieldset above which these are updated
+// with a single statement. By default the single statement updates are disabled.
+const DefaultBulkUpdateThreshold = 0
+
+// bulkUpdateAlias is the alias of the rows with the updated values in the bulk update query.
+const bulkUpdateAlias = "v"
+
+// isBulkUpdate checks if the 'models' with the same fieldset should be updated with a single statement.
+func (p *Postgres) isBulkUpdate(models []mapping.Model) bool {
+	return p.BulkUpdateThreshold > 0 && len(models) > p.BulkUpdateThreshold
+}
+
+// bulkUpdateQuery is the single statement update query of the models with the same fieldset.
+type bulkUpdateQuery struct {
+	query  string
+	values []interface{}
+	// returning are the fields scanned back into the updated models. The primary key is always returned first,
+	// so that the rows could be matched with the models.
+	returning []*mapping.StructField
+	// version is the optimistic locking version field of the model.
+	version *mapping.StructField
+}
+
+// updateBulkModels updates the 'models' with the same 'fieldSet' using a single statement i.e.:
+// 'UPDATE t SET ... FROM unnest($1::integer[], $2::text[]) AS v(id, name) WHERE t.id = v.id'. The values of each
+// column are sent as a typed array. Returns false if any of the columns could not be sent as a typed array.
+// The models with duplicated primary keys are rejected with the query.ErrInvalidModels error.
+func (p *Postgres) updateBulkModels(ctx context.Context, s *query.Scope, fieldSet mapping.FieldSet, models []mapping.Model) (affected int64, ok bool, err error) {
+	q, ok, err := p.buildBulkUpdateQuery(s, fieldSet, models)
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+	affected, err = p.execBulkUpdate(ctx, s, q, models)
+	return affected, true, err
+}
+
+func (p *Postgres) buildBulkUpdateQuery(s *query.Scope, fieldSet mapping.FieldSet, models []mapping.Model) (*bulkUpdateQuery, bool, error) {
+	fieldSet, err := p.prepareUpdateModelFieldSet(fieldSet)
+	if err != nil {
+		return nil, false, err
+	}
+	if err = validateUpdateExpressions(s); err != nil {
+		return nil, false, err
+	}
+	mStruct := s.ModelStruct
+	expressions := updateExpressions(s)
+	q := &bulkUpdateQuery{}
+
+	// The columns are the primary key, the fields updated with the values and the version.
+	columns := []*mapping.StructField{mStruct.Primary()}
+	for _, field := range fieldSet {
+		if expressions[field].Operator.hasValue() {
+			columns = append(columns, field)
+		}
+	}
+	if version, ok := migrate.VersionField(mStruct); ok {
+		q.version = version
+		columns = append(columns, version)
+	}
+	columnTypes := make([]string, len(columns))
+	for i, column := range columns {
+		columnType, err := migrate.ColumnType(column)
+		if err != nil {
+			return nil, false, nil
+		}
+		// The unnest function flattens the multidimensional arrays.
+		if strings.HasSuffix(columnType, "]") {
+			return nil, false, nil
+		}
+		columnTypes[i] = columnType
+	}
+
+	// Get the column arrays of the models values. The rows returned by the query are matched with the models by
+	// their primary keys, thus these needs to be unique.
+	columnValues := make([][]interface{}, len(columns))
+	primaryKeys := make(map[interface{}]struct{}, len(models))
+	for _, model := range models {
+		fielder, ok := model.(mapping.Fielder)
+		if !ok {
+			return nil, false, errors.Wrapf(mapping.ErrModelNotImplements, "model: '%s' doesn't implement Fielder interface", mStruct)
+		}
+		primaryKey := model.GetPrimaryKeyHashableValue()
+		if _, ok = primaryKeys[primaryKey]; ok {
+			return nil, false, errors.WrapDetf(query.ErrInvalidModels, "model: '%s' with the primary key: '%v' is duplicated in the bulk update", mStruct, primaryKey)
+		}
+		primaryKeys[primaryKey] = struct{}{}
+		values, err := updateFieldValues(s, fieldSet, fielder)
+		if err != nil {
+			return nil, false, err
+		}
+		values = append([]interface{}{model.GetPrimaryKeyValue()}, values...)
+		if q.version != nil {
+			version, err := fielder.GetFieldValue(q.version)
+			if err != nil {
+				return nil, false, err
+			}
+			values = append(values, version)
+		}
+		for i, value := range values {
+			columnValues[i] = append(columnValues[i], value)
+		}
+	}
+	arrays := make([]interface{}, len(columns))
+	for i, column := range columns {
+		array, ok := internal.TypedArrayValue(column.ReflectField().Type, columnValues[i])
+		if !ok {
+			return nil, false, nil
+		}
+		arrays[i] = array
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString("UPDATE ")
+	p.writeTableName(sb, mStruct)
+	sb.WriteString(" AS t SET ")
+	for i, field := range fieldSet {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		operator := expressions[field].Operator
+		column := &strings.Builder{}
+		p.writeQualifiedColumn(column, "t", field)
+		value := &strings.Builder{}
+		if operator.hasValue() {
+			p.writeQualifiedColumn(value, bulkUpdateAlias, field)
+		}
+		p.writeUpdateExpression(sb, field, operator, column.String(), value.String())
+	}
+	if updatedAt, ok := autoUpdatedAt(s, fieldSet); ok {
+		sb.WriteString(", ")
+		p.writeQuotedWord(sb, updatedAt.DatabaseName)
+		sb.WriteString(" = ")
+		q.values = p.writeTimestamp(s, sb, q.values, p.clockValue())
+		q.returning = append(q.returning, updatedAt)
+	}
+	if q.version != nil {
+		sb.WriteString(", ")
+		p.writeQuotedWord(sb, q.version.DatabaseName)
+		sb.WriteString(" = ")
+		p.writeQualifiedColumn(sb, "t", q.version)
+		sb.WriteString(" + 1")
+		q.returning = append(q.returning, q.version)
+	}
+	q.returning = append(q.returning, computedFields(s, fieldSet)...)
+
+	// FROM unnest($2::integer[], $3::text[]) AS v(id, name)
+	sb.WriteString(" FROM unnest(")
+	for i, columnType := range columnTypes {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(internal.StringIncrementor(s))
+		sb.WriteString("::")
+		sb.WriteString(columnType)
+		sb.WriteString("[]")
+	}
+	q.values = append(q.values, arrays...)
+	sb.WriteString(") AS ")
+	sb.WriteString(bulkUpdateAlias)
+	sb.WriteRune('(')
+	for i, column := range columns {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		p.writeQuotedWord(sb, column.DatabaseName)
+	}
+	sb.WriteRune(')')
+
+	sb.WriteString(" WHERE ")
+	p.writeQualifiedColumn(sb, "t", mStruct.Primary())
+	sb.WriteString(" = ")
+	p.writeQualifiedColumn(sb, bulkUpdateAlias, mStruct.Primary())
+	// The versioned models are updated only if their versions were not changed in the meantime.
+	if q.version != nil {
+		sb.WriteString(" AND ")
+		p.writeQualifiedColumn(sb, "t", q.version)
+		sb.WriteString(" = ")
+		p.writeQualifiedColumn(sb, bulkUpdateAlias, q.version)
+	}
+	if softDeleted, ok := p.updateSoftDeleteFilter(s, fieldSet); ok {
+		sb.WriteString(" AND t.")
+		sb.WriteString(softDeleted.Query)
+	}
+	if fields, ok := returnUpdatedFields(s); ok {
+		for _, field := range fields {
+			if field != mStruct.Primary() && !containsField(q.returning, field) {
+				q.returning = append(q.returning, field)
+			}
+		}
+	}
+	q.returning = append([]*mapping.StructField{mStruct.Primary()}, q.returning...)
+	sb.WriteString(" RETURNING ")
+	for i, field := range q.returning {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		p.writeQualifiedColumn(sb, "t", field)
+	}
+	q.query = sb.String()
+	return q, true, nil
+}
+
+// execBulkUpdate executes the bulk update query and scans the returned rows into the 'models' with matching primary
+// keys. If any of the versioned models was not updated, the StaleModelsError with all such models is returned.
+func (p *Postgres) execBulkUpdate(ctx context.Context, s *query.Scope, q *bulkUpdateQuery, models []mapping.Model) (affected int64, err error) {
+	rows, err := p.connection(s).Query(ctx, q.query, q.values...)
+	if err != nil {
+		return 0, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+	}
+	affected, stale, err := p.scanBulkUpdateRows(s, q, rows, models)
+	if err != nil {
+		return affected, err
+	}
+	if len(stale) > 0 {
+		return affected, &StaleModelsError{Models: stale}
+	}
+	return affected, nil
+}
+
+// scanBulkUpdateRows scans and closes the 'rows' returned by the bulk update query. The rows are scanned into
+// the 'models' with matching primary keys. Returns the versioned models that were not updated.
+func (p *Postgres) scanBulkUpdateRows(s *query.Scope, q *bulkUpdateQuery, rows pgx.Rows, models []mapping.Model) (affected int64, stale []mapping.Model, err error) {
+	defer rows.Close()
+	if log.Level().IsAllowed(log.LevelDebug2) {
+		log.Debug2f("[UPDATE] %s - %d models", q.query, len(models))
+	}
+	byPrimaryKey := make(map[interface{}]mapping.Model, len(models))
+	for _, model := range models {
+		byPrimaryKey[model.GetPrimaryKeyHashableValue()] = model
+	}
+
+	updated := map[interface{}]struct{}{}
+	for rows.Next() {
+		scanner, err := newModelScanner(s.ModelStruct, q.returning)
+		if err != nil {
+			return affected, nil, err
+		}
+		if err = rows.Scan(scanner.values...); err != nil {
+			return affected, nil, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+		}
+		if err = scanner.setTimePointers(); err != nil {
+			return affected, nil, err
+		}
+		primaryKey := scanner.model.GetPrimaryKeyHashableValue()
+		model, ok := byPrimaryKey[primaryKey]
+		if !ok {
+			continue
+		}
+		if err = setReturnedFields(scanner, model, q.returning[1:]); err != nil {
+			return affected, nil, err
+		}
+		updated[primaryKey] = struct{}{}
+		affected++
+	}
+	if err = rows.Err(); err != nil {
+		return affected, nil, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+	}
+
+	if q.version == nil {
+		return affected, nil, nil
+	}
+	for _, model := range models {
+		if _, ok := updated[model.GetPrimaryKeyHashableValue()]; !ok {
+			stale = append(stale, model)
+		}
+	}
+	return affected, stale, nil
+}
+
+// setReturnedFields sets the 'fields' values scanned by the 'scanner' in the 'model'.
+func setReturnedFields(scanner *modelScanner, model mapping.Model, fields []*mapping.StructField) error {
+	fielder, ok := model.(mapping.Fielder)
+	if !ok {
+		return errors.Wrapf(mapping.ErrModelNotImplements, "model: '%T' doesn't implement Fielder interface", model)
+	}
+	for _, field := range fields {
+		value, err := scanner.fielder.GetFieldValue(field)
+		if err != nil {
+			return err
+		}
+		if err = fielder.SetFieldValue(field, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+)
+
+// UpdateOperator is the operation that sets the column value in the update query.
+type UpdateOperator int
+
+const (
+	// UpdateSet sets the column to the value: 'col = $1'. It is the default operation of the updated fields.
+	UpdateSet UpdateOperator = iota
+	// UpdateIncrement adds the value to the column: 'col = col + $1'. Negative values decrement the column.
+	UpdateIncrement
+	// UpdateAppend appends the value to the array column: 'col = array_append(col, $1)'.
+	UpdateAppend
+	// UpdateCoalesce sets the column to the value only if it is null: 'col = COALESCE(col, $1)'.
+	UpdateCoalesce
+	// UpdateNow sets the column to the current database time: 'col = now()'.
+	UpdateNow
+	// UpdateDefault sets the column to its default value: 'col = DEFAULT'.
+	UpdateDefault
+)
+
+// String implements fmt.Stringer interface.
+func (u UpdateOperator) String() string {
+	switch u {
+	case UpdateSet:
+		return "set"
+	case UpdateIncrement:
+		return "increment"
+	case UpdateAppend:
+		return "append"
+	case UpdateCoalesce:
+		return "coalesce"
+	case UpdateNow:
+		return "now"
+	case UpdateDefault:
+		return "default"
+	default:
+		return "unknown"
+	}
+}
+
+// hasValue checks if the operator takes the value argument.
+func (u UpdateOperator) hasValue() bool {
+	switch u {
+	case UpdateNow, UpdateDefault:
+		return false
+	default:
+		return true
+	}
+}
+
+// UpdateExpression defines how the field is set by the update query. The expressions are evaluated by the database
+// within a single update query, thus these are atomic i.e. the counters could be incremented without reading them first.
+type UpdateExpression struct {
+	// Field is the updated field. It needs to be in the update field set.
+	Field *mapping.StructField
+	// Operator is the update operation of the field.
+	Operator UpdateOperator
+	// Value is the argument of the operation. If it is not set, the model's field value is used.
+	// The UpdateAppend operation requires the Value to be set to the appended array element.
+	Value interface{}
+}
+
+func (u UpdateExpression) validate(mStruct *mapping.ModelStruct) error {
+	if u.Field == nil || u.Field.ModelStruct() != mStruct {
+		return errors.WrapDetf(query.ErrInvalidField, "update expression field doesn't belong to the model: '%s'", mStruct)
+	}
+	switch u.Operator {
+	case UpdateSet, UpdateIncrement, UpdateCoalesce, UpdateNow, UpdateDefault:
+	case UpdateAppend:
+		if u.Value == nil {
+			return errors.WrapDetf(query.ErrInvalidField, "update expression of the field: '%s' requires the appended value", u.Field)
+		}
+		if !u.Field.IsSlice() && !u.Field.IsArray() {
+			return errors.WrapDetf(query.ErrInvalidField, "cannot append to the non array field: '%s'", u.Field)
+		}
+	default:
+		return errors.WrapDetf(query.ErrInvalidField, "invalid update operator: '%d' of the field: '%s'", u.Operator, u.Field)
+	}
+	return nil
+}
+
+// UpdateWith sets the scope option that defines how the fields are set by the update query i.e. incremented
+// or appended. It applies to both the model and the filter updates. The expressions of the fields that
+// are not in the update field set are not used.
+func UpdateWith(s *query.Scope, expressions ...UpdateExpression) {
+	stored := updateExpressions(s)
+	if stored == nil {
+		stored = map[*mapping.StructField]UpdateExpression{}
+		s.StoreSet(internal.UpdateExpressionsKey, stored)
+	}
+	for _, expression := range expressions {
+		stored[expression.Field] = expression
+	}
+}
+
+// updateExpressions gets the update expressions of the scope's fields set by the UpdateWith option.
+func updateExpressions(s *query.Scope) map[*mapping.StructField]UpdateExpression {
+	v, ok := s.StoreGet(internal.UpdateExpressionsKey)
+	if !ok {
+		return nil
+	}
+	expressions, _ := v.(map[*mapping.StructField]UpdateExpression)
+	return expressions
+}
+
+// writeUpdateExpression writes the 'field' set clause with given 'operator' i.e.: 'col = col + $1'. The 'column' is
+// the reference of the current column value and the 'value' is the operation argument.
+func (p *Postgres) writeUpdateExpression(sb *strings.Builder, field *mapping.StructField, operator UpdateOperator, column, value string) {
+	p.writeQuotedWord(sb, field.DatabaseName)
+	sb.WriteString(" = ")
+	switch operator {
+	case UpdateIncrement:
+		sb.WriteString(column)
+		sb.WriteString(" + ")
+		sb.WriteString(value)
+	case UpdateAppend:
+		sb.WriteString("array_append(")
+		sb.WriteString(column)
+		sb.WriteString(", ")
+		sb.WriteString(value)
+		sb.WriteRune(')')
+	case UpdateCoalesce:
+		sb.WriteString("COALESCE(")
+		sb.WriteString(column)
+		sb.WriteString(", ")
+		sb.WriteString(value)
+		sb.WriteRune(')')
+	case UpdateNow:
+		sb.WriteString("now()")
+	case UpdateDefault:
+		sb.WriteString("DEFAULT")
+	default:
+		sb.WriteString(value)
+	}
+}
+
+// validateUpdateExpressions checks if the scope's update expressions are valid.
+func validateUpdateExpressions(s *query.Scope) error {
+	for _, expression := range updateExpressions(s) {
+		if err := expression.validate(s.ModelStruct); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateFieldValues gets the update query arguments of the 'fieldSet' for given 'fielder'. The fields set with
+// the expressions without arguments are skipped.
+func updateFieldValues(s *query.Scope, fieldSet mapping.FieldSet, fielder mapping.Fielder) ([]interface{}, error) {
+	expressions := updateExpressions(s)
+	values := make([]interface{}, 0, len(fieldSet))
+	for _, field := range fieldSet {
+		expression, ok := expressions[field]
+		if ok && !expression.Operator.hasValue() {
+			continue
+		}
+		if ok && expression.Value != nil {
+			values = append(values, expression.Value)
+			continue
+		}
+		fieldValue, err := fielder.GetFieldValue(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, fieldValue)
+	}
+	return values, nil
+}
+
+// computedFields gets the 'fieldSet' fields which values are computed by the database using the update expressions,
+// i.e. incremented, thus these needs to be scanned back into the updated models.
+func computedFields(s *query.Scope, fieldSet mapping.FieldSet) (fields []*mapping.StructField) {
+	expressions := updateExpressions(s)
+	for _, field := range fieldSet {
+		if expression, ok := expressions[field]; ok && expression.Operator != UpdateSet {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
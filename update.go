@@ -7,12 +7,12 @@ import (
 
 	"github.com/jackc/pgx/v4"
 
-	"github.com/neuronlabs/neuron-extensions/repository/postgres/filters"
 	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
 
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
 )
 
 // Update patches all the values that matches scope's filters, sorts and pagination
@@ -44,28 +44,34 @@ func (p *Postgres) updateModels(ctx context.Context, s *query.Scope) (affected i
 			}
 			return p.updatedModelWithFieldset(ctx, s, fieldSet, model)
 		}
-		b := &pgx.Batch{}
-		if err := p.updateBatchModelsWithFieldSet(s, b, fieldSet, s.Models...); err != nil {
-			return 0, err
-		}
-
-		results := p.connection(s).SendBatch(ctx, b)
-		defer results.Close()
-		for i := 0; i < b.Len(); i++ {
-			tag, err := results.Exec()
-			if err != nil {
+		if p.isBulkUpdate(s.Models) {
+			affected, ok, err := p.updateBulkModels(ctx, s, fieldSet, s.Models)
+			if ok || err != nil {
 				return affected, err
 			}
-			affected += tag.RowsAffected()
+			internal.ResetIncrementor(s)
 		}
-		return affected, nil
+		b := &pgx.Batch{}
+		q, err := p.updateBatchModelsWithFieldSet(s, b, fieldSet, s.Models...)
+		if err != nil {
+			return 0, err
+		}
+		return p.execUpdateBatch(ctx, s, b, []*batchUpdate{{models: s.Models, query: q}})
 	default:
 		return p.updateModelsWithBulkFieldSet(ctx, s)
 	}
 }
 
+// batchUpdate are the models queued in the update batch with the same query.
+type batchUpdate struct {
+	models []mapping.Model
+	query  *updateModelQuery
+	// bulk is the single statement query of all the models. If set the query is not used.
+	bulk *bulkUpdateQuery
+}
+
 func (p *Postgres) updateModelsWithBulkFieldSet(ctx context.Context, s *query.Scope) (affected int64, err error) {
-	var models []mapping.Model
+	var updates []*batchUpdate
 	b := &pgx.Batch{}
 	// For each unique fieldset create a query that would be executed for each matched model.
 	// This would result in a query for each model.
@@ -75,26 +81,80 @@ func (p *Postgres) updateModelsWithBulkFieldSet(ctx context.Context, s *query.Sc
 	}
 
 	for _, fieldSet := range bulk.FieldSets {
+		var models []mapping.Model
 		indices := bulk.GetIndicesByFieldset(fieldSet)
 		for _, index := range indices {
 			models = append(models, s.Models[index])
 		}
-		if err = p.updateBatchModelsWithFieldSet(s, b, fieldSet, models...); err != nil {
+		// The large groups of the models are updated with a single statement queued in the same batch.
+		if p.isBulkUpdate(models) {
+			bq, ok, err := p.buildBulkUpdateQuery(s, fieldSet, models)
+			internal.ResetIncrementor(s)
+			if err != nil {
+				return 0, err
+			}
+			if ok {
+				b.Queue(bq.query, bq.values...)
+				updates = append(updates, &batchUpdate{models: models, bulk: bq})
+				continue
+			}
+		}
+		q, err := p.updateBatchModelsWithFieldSet(s, b, fieldSet, models...)
+		if err != nil {
 			if !errors.Is(err, query.ErrNoFieldsInFieldSet) {
-				return affected, err
+				return 0, err
 			}
+		} else {
+			updates = append(updates, &batchUpdate{models: models, query: q})
 		}
 		internal.ResetIncrementor(s)
 	}
+	return p.execUpdateBatch(ctx, s, b, updates)
+}
 
+// execUpdateBatch sends the batch of the model update queries and sums up the affected rows.
+// The returning fields of the queries are scanned back into the models. If any of the versioned models
+// was not updated, the StaleModelsError with all such models is returned.
+func (p *Postgres) execUpdateBatch(ctx context.Context, s *query.Scope, b *pgx.Batch, updates []*batchUpdate) (affected int64, err error) {
 	results := p.connection(s).SendBatch(ctx, b)
 	defer results.Close()
-	for i := 0; i < b.Len(); i++ {
-		tag, err := results.Exec()
-		if err != nil {
-			return affected, err
+	var stale []mapping.Model
+	for _, update := range updates {
+		if update.bulk != nil {
+			rows, err := results.Query()
+			if err != nil {
+				return affected, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+			}
+			bulkAffected, bulkStale, err := p.scanBulkUpdateRows(s, update.bulk, rows, update.models)
+			affected += bulkAffected
+			if err != nil {
+				return affected, err
+			}
+			stale = append(stale, bulkStale...)
+			continue
+		}
+		for _, model := range update.models {
+			if len(update.query.returning) == 0 {
+				tag, err := results.Exec()
+				if err != nil {
+					return affected, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+				}
+				affected += tag.RowsAffected()
+				continue
+			}
+			found, err := scanReturningRow(results.QueryRow(), model, update.query.returning)
+			if err != nil {
+				return affected, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+			}
+			if found {
+				affected++
+			} else if update.query.version != nil {
+				stale = append(stale, model)
+			}
 		}
-		affected += tag.RowsAffected()
+	}
+	if len(stale) > 0 {
+		return affected, &StaleModelsError{Models: stale}
 	}
 	return affected, nil
 }
@@ -109,27 +169,25 @@ func (p *Postgres) updatedModelWithFieldset(ctx context.Context, s *query.Scope,
 	if err != nil {
 		return 0, err
 	}
-	fielder, ok := model.(mapping.Fielder)
-	if !ok {
-		return 0, errors.Wrapf(mapping.ErrModelNotImplements, "model: '%s' doesn't implement Fielder interface", s.ModelStruct)
+	modelValues, err := q.modelValues(s, fieldSet, model)
+	if err != nil {
+		return 0, err
 	}
 
-	var (
-		modelValues []interface{}
-	)
-	primaryValue := model.GetPrimaryKeyValue()
-	for _, field := range fieldSet {
-		fieldValue, err := fielder.GetFieldValue(field)
+	if len(q.returning) > 0 {
+		found, err := scanReturningRow(p.connection(s).QueryRow(ctx, q.query, modelValues...), model, q.returning)
 		if err != nil {
-			return affected, err
+			return affected, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+		}
+		if found {
+			affected = 1
+		} else if q.version != nil {
+			return 0, &StaleModelsError{Models: []mapping.Model{model}}
 		}
-		modelValues = append(modelValues, fieldValue)
+		return affected, nil
 	}
 
-	// Primary key value must be the last one - it would be set as the filter value.
-	modelValues = append(modelValues, primaryValue)
-
-	tag, err := p.connection(s).Exec(ctx, q, modelValues...)
+	tag, err := p.connection(s).Exec(ctx, q.query, modelValues...)
 	if err != nil {
 		return affected, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
 	}
@@ -137,55 +195,116 @@ func (p *Postgres) updatedModelWithFieldset(ctx context.Context, s *query.Scope,
 	return tag.RowsAffected(), nil
 }
 
-func (p *Postgres) updateBatchModelsWithFieldSet(s *query.Scope, b internal.Batch, fieldSet mapping.FieldSet, models ...mapping.Model) (err error) {
-	fieldSet, err = p.prepareUpdateModelFieldSet(fieldSet)
+func (p *Postgres) updateBatchModelsWithFieldSet(s *query.Scope, b internal.Batch, fieldSet mapping.FieldSet, models ...mapping.Model) (*updateModelQuery, error) {
+	fieldSet, err := p.prepareUpdateModelFieldSet(fieldSet)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	q, err := p.buildUpdateModelQuery(s, fieldSet)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, model := range models {
-		fielder, ok := model.(mapping.Fielder)
-		if !ok {
-			return errors.Wrapf(mapping.ErrModelNotImplements, "model: '%s' doesn't implement Fielder interface", s.ModelStruct)
-		}
-		var (
-			modelValues []interface{}
-		)
-		primaryValue := model.GetPrimaryKeyValue()
-		for _, field := range fieldSet {
-			fieldValue, err := fielder.GetFieldValue(field)
-			if err != nil {
-				return err
-			}
-			modelValues = append(modelValues, fieldValue)
+		modelValues, err := q.modelValues(s, fieldSet, model)
+		if err != nil {
+			return nil, err
 		}
-		// Primary key value must be the last one - it would be set as the filter value.
-		modelValues = append(modelValues, primaryValue)
+		b.Queue(q.query, modelValues...)
+	}
+	return q, nil
+}
 
-		b.Queue(q, modelValues...)
+// updateModelQuery is the update query for the models with the same fieldset.
+type updateModelQuery struct {
+	query string
+	// timestampValues are the arguments of the automatically set timestamps.
+	timestampValues []interface{}
+	// returning are the fields scanned back into the updated models.
+	returning []*mapping.StructField
+	// version is the optimistic locking version field of the model.
+	version *mapping.StructField
+}
+
+// modelValues gets the query arguments for given 'model'.
+func (q *updateModelQuery) modelValues(s *query.Scope, fieldSet mapping.FieldSet, model mapping.Model) ([]interface{}, error) {
+	fielder, ok := model.(mapping.Fielder)
+	if !ok {
+		return nil, errors.Wrapf(mapping.ErrModelNotImplements, "model: '%s' doesn't implement Fielder interface", s.ModelStruct)
+	}
+	modelValues, err := updateFieldValues(s, fieldSet, fielder)
+	if err != nil {
+		return nil, err
+	}
+	modelValues = append(modelValues, q.timestampValues...)
+	// Primary key value must be the last one - it would be set as the filter value.
+	modelValues = append(modelValues, model.GetPrimaryKeyValue())
+	if q.version != nil {
+		version, err := fielder.GetFieldValue(q.version)
+		if err != nil {
+			return nil, err
+		}
+		modelValues = append(modelValues, version)
 	}
-	return nil
+	return modelValues, nil
 }
 
-func (p *Postgres) buildUpdateModelQuery(s *query.Scope, fieldSet mapping.FieldSet) (string, error) {
+func (p *Postgres) buildUpdateModelQuery(s *query.Scope, fieldSet mapping.FieldSet) (*updateModelQuery, error) {
+	q := &updateModelQuery{}
 	sb := &strings.Builder{}
-	if err := p.buildUpdateQuery(s, fieldSet, sb); err != nil {
-		return "", err
+	var err error
+	if q.timestampValues, err = p.buildUpdateQuery(s, fieldSet, sb); err != nil {
+		return nil, err
 	}
 	sb.WriteString(" WHERE ")
 	sb.WriteString(s.ModelStruct.Primary().DatabaseName)
 	sb.WriteString(" = $")
 	sb.WriteString(strconv.Itoa(internal.Incrementor(s)))
-	q := sb.String()
+	// The versioned model is updated only if its version was not changed in the meantime.
+	if version, ok := migrate.VersionField(s.ModelStruct); ok {
+		q.version = version
+		sb.WriteString(" AND ")
+		p.writeQuotedWord(sb, version.DatabaseName)
+		sb.WriteString(" = $")
+		sb.WriteString(strconv.Itoa(internal.Incrementor(s)))
+	}
+	if softDeleted, ok := p.updateSoftDeleteFilter(s, fieldSet); ok {
+		sb.WriteString(" AND ")
+		sb.WriteString(softDeleted.Query)
+	}
+	// The automatically set UpdatedAt timestamp is scanned back into the models.
+	if updatedAt, ok := autoUpdatedAt(s, fieldSet); ok {
+		q.returning = append(q.returning, updatedAt)
+	}
+	// The incremented version is scanned back, so that the model could be updated again.
+	if q.version != nil {
+		q.returning = append(q.returning, q.version)
+	}
+	// The fields set with the update expressions are scanned back, as their values are computed by the database.
+	q.returning = append(q.returning, computedFields(s, fieldSet)...)
+	// The fields requested by the ReturnUpdated option are scanned back into the model with matching primary key.
+	if fields, ok := returnUpdatedFields(s); ok {
+		for _, field := range fields {
+			if !containsField(q.returning, field) {
+				q.returning = append(q.returning, field)
+			}
+		}
+	}
+	p.writeReturning(sb, q.returning)
+	q.query = sb.String()
 	return q, nil
 }
 
-func (p *Postgres) buildUpdateQuery(s *query.Scope, fieldSet mapping.FieldSet, sb *strings.Builder) error {
+// buildUpdateQuery writes the update query with the 'fieldSet' columns. The columns are set with the expressions
+// defined by the UpdateWith option. If the model has the UpdatedAt field which is not in the 'fieldSet' it is set
+// automatically. The model's version field is incremented.
+// The function returns the timestamp arguments that needs to be placed right after the fieldset values.
+func (p *Postgres) buildUpdateQuery(s *query.Scope, fieldSet mapping.FieldSet, sb *strings.Builder) (timestampValues []interface{}, err error) {
+	if err = validateUpdateExpressions(s); err != nil {
+		return nil, err
+	}
+	expressions := updateExpressions(s)
 	sb.WriteString("UPDATE ")
 	p.writeQuotedWord(sb, s.ModelStruct.DatabaseSchemaName)
 	sb.WriteRune('.')
@@ -193,58 +312,106 @@ func (p *Postgres) buildUpdateQuery(s *query.Scope, fieldSet mapping.FieldSet, s
 	sb.WriteString(" SET ")
 
 	for i, field := range fieldSet {
-		sb.WriteString(field.DatabaseName)
-		sb.WriteString(" = ")
-		sb.WriteRune('$')
-		sb.WriteString(strconv.Itoa(internal.Incrementor(s)))
+		operator := expressions[field].Operator
+		var value string
+		if operator.hasValue() {
+			value = internal.StringIncrementor(s)
+		}
+		column := &strings.Builder{}
+		p.writeQuotedWord(column, field.DatabaseName)
+		p.writeUpdateExpression(sb, field, operator, column.String(), value)
 		if i != len(fieldSet)-1 {
 			sb.WriteString(", ")
 		}
 	}
-	return nil
+	if updatedAt, ok := autoUpdatedAt(s, fieldSet); ok {
+		sb.WriteString(", ")
+		p.writeQuotedWord(sb, updatedAt.DatabaseName)
+		sb.WriteString(" = ")
+		timestampValues = p.writeTimestamp(s, sb, timestampValues, p.clockValue())
+	}
+	// The 'fieldSet' doesn't contain the version field - it is always incremented.
+	if version, ok := migrate.VersionField(s.ModelStruct); ok {
+		sb.WriteString(", ")
+		p.writeVersionIncrement(sb, version)
+	}
+	return timestampValues, nil
+}
+
+// autoUpdatedAt gets the model's UpdatedAt field if it needs to be set automatically by the update query.
+func autoUpdatedAt(s *query.Scope, fieldSet mapping.FieldSet) (*mapping.StructField, bool) {
+	updatedAt, ok := s.ModelStruct.UpdatedAt()
+	if !ok || updatedAt.DatabaseSkip() || fieldSet.Contains(updatedAt) {
+		return nil, false
+	}
+	return updatedAt, true
 }
 
 func (p *Postgres) updateWithFilters(ctx context.Context, s *query.Scope) (int64, error) {
+	sb, values, err := p.buildUpdateWithFiltersQuery(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if returning, ok := returnUpdatedFields(s); ok {
+		p.writeReturning(sb, returning)
+		return p.updateReturning(ctx, s, sb.String(), values, returning)
+	}
+
+	tag, err := p.connection(s).Exec(ctx, sb.String(), values...)
+	if err != nil {
+		return 0, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// buildUpdateWithFiltersQuery builds the query that updates the rows matching the scope's filters with the values
+// of the scope's single model. The version field of the versioned models is always incremented.
+func (p *Postgres) buildUpdateWithFiltersQuery(s *query.Scope) (*strings.Builder, []interface{}, error) {
 	// Check if there is anything to update.
 	if len(s.FieldSets) != 1 {
-		return 0, errors.Wrap(query.ErrInvalidFieldSet, "provided empty fieldset length - update with filters")
+		return nil, nil, errors.Wrap(query.ErrInvalidFieldSet, "provided empty fieldset length - update with filters")
 	}
-
-	fieldSet := s.FieldSets[0]
-	if len(fieldSet) == 0 {
-		return 0, errors.Wrap(query.ErrInvalidFieldSet, "provided empty fieldset - update with filters")
+	if len(s.FieldSets[0]) == 0 {
+		return nil, nil, errors.Wrap(query.ErrInvalidFieldSet, "provided empty fieldset - update with filters")
 	}
 
 	// Check if there is exactly one model.
 	if len(s.Models) != 1 {
-		return 0, errors.Wrap(query.ErrInvalidModels, "update with filters require exactly one model")
+		return nil, nil, errors.Wrap(query.ErrInvalidModels, "update with filters require exactly one model")
+	}
+
+	// The version field is incremented by the update query, thus it is not set with the model's value.
+	fieldSet, err := p.prepareUpdateModelFieldSet(s.FieldSets[0])
+	if err != nil {
+		return nil, nil, err
 	}
 
 	sb := &strings.Builder{}
 	// Build update query.
-	if err := p.buildUpdateQuery(s, fieldSet, sb); err != nil {
-		return 0, err
+	timestampValues, err := p.buildUpdateQuery(s, fieldSet, sb)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Get model fielder and get it's fields values.
-	var values []interface{}
 	fielder, ok := s.Models[0].(mapping.Fielder)
 	if !ok {
-		return 0, errors.Wrap(mapping.ErrModelNotImplements, "model doesn't implement Fielder interface")
+		return nil, nil, errors.Wrap(mapping.ErrModelNotImplements, "model doesn't implement Fielder interface")
 	}
-
-	for _, field := range fieldSet {
-		fieldValue, err := fielder.GetFieldValue(field)
-		if err != nil {
-			return 0, err
-		}
-		values = append(values, fieldValue)
+	values, err := updateFieldValues(s, fieldSet, fielder)
+	if err != nil {
+		return nil, nil, err
 	}
+	values = append(values, timestampValues...)
 
 	// Parse filters and store in the string builder.
-	parsedFilters, err := filters.ParseFilters(s, p.writeQuotedWord)
+	parsedFilters, err := p.parseModifyFilters(s)
 	if err != nil {
-		return 0, err
+		return nil, nil, err
+	}
+	if softDeleted, ok := p.updateSoftDeleteFilter(s, fieldSet); ok {
+		parsedFilters = append(parsedFilters, softDeleted)
 	}
 
 	if len(parsedFilters) > 0 {
@@ -257,10 +424,30 @@ func (p *Postgres) updateWithFilters(ctx context.Context, s *query.Scope) (int64
 			values = append(values, f.Values...)
 		}
 	}
+	return sb, values, nil
+}
 
-	tag, err := p.connection(s).Exec(ctx, sb.String(), values...)
+// updateReturning executes the update with filters query that returns the 'returning' fields of the updated rows.
+// The updated rows replace the scope's models.
+func (p *Postgres) updateReturning(ctx context.Context, s *query.Scope, q string, values []interface{}, returning []*mapping.StructField) (int64, error) {
+	rows, err := p.connection(s).Query(ctx, q, values...)
 	if err != nil {
 		return 0, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
 	}
-	return tag.RowsAffected(), nil
+	defer rows.Close()
+
+	models := s.Models
+	s.Models = nil
+	sq := &selectQuery{fieldsOrder: returning}
+	for rows.Next() {
+		if err = p.scanRow(s, sq, rows); err != nil {
+			s.Models = models
+			return 0, errors.Wrap(p.neuronError(err), "scanning updated row failed")
+		}
+	}
+	if err = rows.Err(); err != nil {
+		s.Models = models
+		return 0, errors.WrapDetf(p.neuronError(err), "update failed: %v", err)
+	}
+	return int64(len(s.Models)), nil
 }
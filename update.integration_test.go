@@ -13,7 +13,9 @@ import (
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
 	"github.com/neuronlabs/neuron/database"
 	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 )
 
 // // TestIntegrationPatch integration tests for update method.
@@ -81,6 +83,109 @@ func TestUpdate(t *testing.T) {
 
 		assert.Equal(t, int64(2), affected)
 	})
+
+	t.Run("Returning", func(t *testing.T) {
+		model := &tests.SimpleModel{ID: model1.ID, Attr: "Returned"}
+		s := query.NewScope(mStruct, model)
+		s.FieldSets = []mapping.FieldSet{{mStruct.MustFieldByName("Attr")}}
+		ReturnUpdated(s, mStruct.MustFieldByName("CreatedAt"))
+		affected, err := p.Update(ctx, s)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), affected)
+		assert.NotNil(t, model.CreatedAt)
+
+		s = query.NewScope(mStruct, &tests.SimpleModel{Attr: "Filtered"})
+		s.FieldSets = []mapping.FieldSet{{mStruct.MustFieldByName("Attr")}}
+		s.Filters = filter.Filters{filter.New(mStruct.Primary(), filter.OpIn, model1.ID, model2.ID)}
+		ReturnUpdated(s)
+		affected, err = p.Update(ctx, s)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2), affected)
+		if assert.Len(t, s.Models, 2) {
+			for _, updated := range s.Models {
+				assert.Equal(t, "Filtered", updated.(*tests.SimpleModel).Attr)
+				assert.NotZero(t, updated.(*tests.SimpleModel).ID)
+			}
+		}
+	})
+
+	t.Run("Bulk", func(t *testing.T) {
+		p.BulkUpdateThreshold = 1
+		defer func() {
+			p.BulkUpdateThreshold = DefaultBulkUpdateThreshold
+		}()
+		affected, err := db.Query(mStruct, &tests.SimpleModel{ID: model1.ID, Attr: "First"}, &tests.SimpleModel{ID: model2.ID, Attr: "Second"}).
+			Select(mStruct.MustFieldByName("Attr")).Update()
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), affected)
+
+		models, err := db.Query(mStruct).Where("ID in", model1.ID, model2.ID).Find()
+		require.NoError(t, err)
+		expected := map[int]string{model1.ID: "First", model2.ID: "Second"}
+		if assert.Len(t, models, 2) {
+			for _, model := range models {
+				assert.Equal(t, expected[model.(*tests.SimpleModel).ID], model.(*tests.SimpleModel).Attr)
+			}
+		}
+	})
+
+	t.Run("BulkFieldSets", func(t *testing.T) {
+		p.BulkUpdateThreshold = 1
+		defer func() {
+			p.BulkUpdateThreshold = DefaultBulkUpdateThreshold
+		}()
+		// The models with the 'Attr' fieldset are updated with a single statement queued in the same batch
+		// as the model with the other fieldset.
+		first := &tests.SimpleModel{ID: model1.ID, Attr: "BulkFirst"}
+		second := &tests.SimpleModel{ID: model2.ID, Attr: "BulkSecond"}
+		third := &tests.SimpleModel{ID: model1.ID, Attr: "Ignored"}
+		s := query.NewScope(mStruct, first, second, third)
+		s.FieldSets = []mapping.FieldSet{{mStruct.MustFieldByName("Attr")}, {mStruct.MustFieldByName("Attr")}, {mStruct.MustFieldByName("CreatedAt")}}
+		affected, err := p.Update(ctx, s)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), affected)
+
+		models, err := db.Query(mStruct).Where("ID in", model1.ID, model2.ID).Find()
+		require.NoError(t, err)
+		expected := map[int]string{model1.ID: "BulkFirst", model2.ID: "BulkSecond"}
+		if assert.Len(t, models, 2) {
+			for _, model := range models {
+				assert.Equal(t, expected[model.(*tests.SimpleModel).ID], model.(*tests.SimpleModel).Attr)
+			}
+		}
+	})
+}
+
+// TestUpdateRestore tests restoring the soft deleted models by the update of the DeletedAt field.
+func TestUpdateRestore(t *testing.T) {
+	c := testingController(t, true, &tests.Model{})
+	p := testingRepository(c)
+
+	ctx := context.Background()
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	defer func() {
+		_ = internal.DropTables(ctx, p.ConnPool, mStruct.DatabaseName, mStruct.DatabaseSchemaName)
+	}()
+
+	db := database.New(c)
+	model := &tests.Model{AttrString: "deleted"}
+	require.NoError(t, db.Query(mStruct, model).Insert())
+
+	affected, err := db.Query(mStruct, model).Delete()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	affected, err = db.Query(mStruct, &tests.Model{ID: model.ID}).Select(mStruct.MustFieldByName("DeletedAt")).Update()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	models, err := db.Query(mStruct).Where("ID =", model.ID).Find()
+	require.NoError(t, err)
+	assert.Len(t, models, 1)
 }
 
 // func TestIntegrationPatch(t *testing.T) {
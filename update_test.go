@@ -2,18 +2,23 @@ package postgres
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/migrate"
 	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+	"github.com/neuronlabs/neuron/errors"
 	"github.com/neuronlabs/neuron/mapping"
 	"github.com/neuronlabs/neuron/query"
+	"github.com/neuronlabs/neuron/query/filter"
 )
 
 func TestBuildUpdateQuery(t *testing.T) {
-	c := testingController(t, false, &tests.Model{})
+	c := testingController(t, false, &tests.Model{}, &tests.VersionedModel{})
 	p := testingRepository(c)
 
 	t.Run("Model", func(t *testing.T) {
@@ -24,7 +29,7 @@ func TestBuildUpdateQuery(t *testing.T) {
 		q, err := p.buildUpdateModelQuery(s, mapping.FieldSet{mStruct.MustFieldByName("AttrString")})
 		require.NoError(t, err)
 
-		assert.Equal(t, "UPDATE public.models SET attr_string = $1 WHERE id = $2", q)
+		assert.Equal(t, "UPDATE public.models SET attr_string = $1, updated_at = now() WHERE id = $2 AND deleted_at IS NULL RETURNING updated_at", q.query)
 	})
 
 	t.Run("BatchModel", func(t *testing.T) {
@@ -34,11 +39,11 @@ func TestBuildUpdateQuery(t *testing.T) {
 		s := query.NewScope(mStruct, &tests.Model{ID: 1, AttrString: "Name", Int: 50}, &tests.Model{ID: 2, AttrString: "Surname", Int: 100})
 
 		batch := &internal.DummyBatch{}
-		err = p.updateBatchModelsWithFieldSet(s, batch, mapping.FieldSet{mStruct.MustFieldByName("AttrString"), mStruct.MustFieldByName("Int")}, s.Models...)
+		_, err = p.updateBatchModelsWithFieldSet(s, batch, mapping.FieldSet{mStruct.MustFieldByName("AttrString"), mStruct.MustFieldByName("Int")}, s.Models...)
 		require.NoError(t, err)
 		assert.Equal(t, 2, batch.Len())
 		for i, b := range batch.Queries {
-			assert.Equal(t, "UPDATE public.models SET attr_string = $1, int = $2 WHERE id = $3", b.Query)
+			assert.Equal(t, "UPDATE public.models SET attr_string = $1, int = $2, updated_at = now() WHERE id = $3 AND deleted_at IS NULL RETURNING updated_at", b.Query)
 			switch i {
 			case 0:
 				assert.ElementsMatch(t, b.Arguments, []interface{}{"Name", 50, 1})
@@ -47,4 +52,195 @@ func TestBuildUpdateQuery(t *testing.T) {
 			}
 		}
 	})
+	t.Run("Clock", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.Model{})
+		require.NoError(t, err)
+
+		now := time.Date(2020, 7, 17, 12, 0, 0, 0, time.UTC)
+		p.Clock = func() time.Time { return now }
+		defer func() {
+			p.Clock = nil
+		}()
+
+		model := &tests.Model{ID: 3, AttrString: "Name"}
+		s := query.NewScope(mStruct, model)
+		fieldSet := mapping.FieldSet{mStruct.MustFieldByName("AttrString")}
+		q, err := p.buildUpdateModelQuery(s, fieldSet)
+		require.NoError(t, err)
+
+		assert.Equal(t, "UPDATE public.models SET attr_string = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL RETURNING updated_at", q.query)
+		values, err := q.modelValues(s, fieldSet, model)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"Name", now, 3}, values)
+	})
+	t.Run("Versioned", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.VersionedModel{})
+		require.NoError(t, err)
+
+		model := &tests.VersionedModel{ID: 4, Name: "Name", Version: 2}
+		s := query.NewScope(mStruct, model)
+		fieldSet := mapping.FieldSet{mStruct.MustFieldByName("Name")}
+		q, err := p.buildUpdateModelQuery(s, fieldSet)
+		require.NoError(t, err)
+
+		assert.Equal(t, "UPDATE public.versioned_models SET name = $1, version = version + 1 WHERE id = $2 AND version = $3 RETURNING version", q.query)
+		values, err := q.modelValues(s, fieldSet, model)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"Name", 4, 2}, values)
+	})
+	t.Run("Restore", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.Model{})
+		require.NoError(t, err)
+
+		// The update of the DeletedAt field matches the soft deleted rows.
+		s := query.NewScope(mStruct, &tests.Model{ID: 3})
+		q, err := p.buildUpdateModelQuery(s, mapping.FieldSet{mStruct.MustFieldByName("DeletedAt")})
+		require.NoError(t, err)
+
+		assert.Equal(t, "UPDATE public.models SET deleted_at = $1, updated_at = now() WHERE id = $2 RETURNING updated_at", q.query)
+	})
+	t.Run("VersionedFilters", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.VersionedModel{})
+		require.NoError(t, err)
+
+		// The version in the fieldset is not set with the model's value, it is incremented.
+		s := query.NewScope(mStruct, &tests.VersionedModel{Name: "Name", Version: 7})
+		s.FieldSets = []mapping.FieldSet{{mStruct.MustFieldByName("Name"), mStruct.MustFieldByName("Version")}}
+		s.Filters = filter.Filters{filter.New(mStruct.MustFieldByName("Name"), filter.OpEqual, "Other")}
+		sb, values, err := p.buildUpdateWithFiltersQuery(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, "UPDATE public.versioned_models SET name = $1, version = version + 1 WHERE name = $2", sb.String())
+		assert.Equal(t, []interface{}{"Name", "Other"}, values)
+	})
+	t.Run("Returning", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.Model{})
+		require.NoError(t, err)
+
+		s := query.NewScope(mStruct, &tests.Model{ID: 3, AttrString: "Name"})
+		ReturnUpdated(s, mStruct.MustFieldByName("Int"), mStruct.MustFieldByName("UpdatedAt"))
+		q, err := p.buildUpdateModelQuery(s, mapping.FieldSet{mStruct.MustFieldByName("AttrString")})
+		require.NoError(t, err)
+
+		assert.Equal(t, "UPDATE public.models SET attr_string = $1, updated_at = now() WHERE id = $2 AND deleted_at IS NULL RETURNING updated_at, int", q.query)
+		assert.Equal(t, []*mapping.StructField{mStruct.MustFieldByName("UpdatedAt"), mStruct.MustFieldByName("Int")}, q.returning)
+	})
+}
+
+// TestBuildUpdateExpressions tests the update query with the field expressions.
+func TestBuildUpdateExpressions(t *testing.T) {
+	c := testingController(t, false, &tests.Model{}, &tests.ArrayModel{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	model := &tests.Model{ID: 3, AttrString: "Name", Int: 5}
+	s := query.NewScope(mStruct, model)
+	UpdateWith(s,
+		UpdateExpression{Field: mStruct.MustFieldByName("Int"), Operator: UpdateIncrement},
+		UpdateExpression{Field: mStruct.MustFieldByName("AttrString"), Operator: UpdateDefault},
+		UpdateExpression{Field: mStruct.MustFieldByName("CreatedAt"), Operator: UpdateNow},
+	)
+	fieldSet := mapping.FieldSet{mStruct.MustFieldByName("AttrString"), mStruct.MustFieldByName("Int"), mStruct.MustFieldByName("CreatedAt")}
+	q, err := p.buildUpdateModelQuery(s, fieldSet)
+	require.NoError(t, err)
+
+	assert.Equal(t, "UPDATE public.models SET attr_string = DEFAULT, int = int + $1, created_at = now(), updated_at = now() WHERE id = $2 AND deleted_at IS NULL RETURNING updated_at, attr_string, int, created_at", q.query)
+	// The fields computed by the database are scanned back into the model.
+	assert.Equal(t, []*mapping.StructField{mStruct.MustFieldByName("UpdatedAt"), mStruct.MustFieldByName("AttrString"), mStruct.MustFieldByName("Int"), mStruct.MustFieldByName("CreatedAt")}, q.returning)
+	values, err := q.modelValues(s, fieldSet, model)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{5, 3}, values)
+
+	// The keyword columns are quoted in both the assigned column and the current column value.
+	quoted := testingRepository(c)
+	quoted.keywords = map[string]migrate.KeyWordType{"int": migrate.KWUnreservedC}
+	s = query.NewScope(mStruct, model)
+	UpdateWith(s, UpdateExpression{Field: mStruct.MustFieldByName("Int"), Operator: UpdateIncrement})
+	q, err = quoted.buildUpdateModelQuery(s, mapping.FieldSet{mStruct.MustFieldByName("Int")})
+	require.NoError(t, err)
+	assert.Equal(t, `UPDATE public.models SET "int" = "int" + $1, updated_at = now() WHERE id = $2 AND deleted_at IS NULL RETURNING updated_at, "int"`, q.query)
+
+	arrayStruct, err := c.ModelStruct(&tests.ArrayModel{})
+	require.NoError(t, err)
+
+	s = query.NewScope(arrayStruct, &tests.ArrayModel{ID: uuid.New()})
+	UpdateWith(s, UpdateExpression{Field: arrayStruct.MustFieldByName("SliceInt"), Operator: UpdateAppend})
+	_, err = p.buildUpdateModelQuery(s, mapping.FieldSet{arrayStruct.MustFieldByName("SliceInt")})
+	assert.True(t, errors.Is(err, query.ErrInvalidField))
+
+	UpdateWith(s, UpdateExpression{Field: arrayStruct.MustFieldByName("SliceInt"), Operator: UpdateAppend, Value: 4})
+	q, err = p.buildUpdateModelQuery(s, mapping.FieldSet{arrayStruct.MustFieldByName("SliceInt")})
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE public.array_models SET slice_int = array_append(slice_int, $1) WHERE id = $2 RETURNING slice_int", q.query)
+}
+
+// TestBuildBulkUpdateQuery tests the single statement update query of the models with the same fieldset.
+func TestBuildBulkUpdateQuery(t *testing.T) {
+	c := testingController(t, false, &tests.Model{}, &tests.VersionedModel{}, &tests.ArrayModel{})
+	p := testingRepository(c)
+
+	t.Run("Model", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.Model{})
+		require.NoError(t, err)
+
+		s := query.NewScope(mStruct, &tests.Model{ID: 1, AttrString: "Name", Int: 5}, &tests.Model{ID: 2, AttrString: "Surname", Int: 10})
+		UpdateWith(s, UpdateExpression{Field: mStruct.MustFieldByName("Int"), Operator: UpdateIncrement})
+		fieldSet := mapping.FieldSet{mStruct.MustFieldByName("AttrString"), mStruct.MustFieldByName("Int")}
+		q, ok, err := p.buildBulkUpdateQuery(s, fieldSet, s.Models)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		assert.Equal(t, "UPDATE public.models AS t SET attr_string = v.attr_string, int = t.int + v.int, updated_at = now() FROM unnest($1::integer[], $2::text[], $3::integer[]) AS v(id, attr_string, int) WHERE t.id = v.id AND t.deleted_at IS NULL RETURNING t.id, t.updated_at, t.int", q.query)
+		assert.Equal(t, []interface{}{[]int{1, 2}, []string{"Name", "Surname"}, []int{5, 10}}, q.values)
+	})
+
+	t.Run("Versioned", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.VersionedModel{})
+		require.NoError(t, err)
+
+		s := query.NewScope(mStruct, &tests.VersionedModel{ID: 1, Name: "Name", Version: 2}, &tests.VersionedModel{ID: 2, Name: "Surname", Version: 4})
+		q, ok, err := p.buildBulkUpdateQuery(s, mapping.FieldSet{mStruct.MustFieldByName("Name")}, s.Models)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		assert.Equal(t, "UPDATE public.versioned_models AS t SET name = v.name, version = t.version + 1 FROM unnest($1::integer[], $2::text[], $3::integer[]) AS v(id, name, version) WHERE t.id = v.id AND t.version = v.version RETURNING t.id, t.version", q.query)
+		assert.Equal(t, []interface{}{[]int{1, 2}, []string{"Name", "Surname"}, []int{2, 4}}, q.values)
+	})
+
+	t.Run("ArrayFields", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.ArrayModel{})
+		require.NoError(t, err)
+
+		s := query.NewScope(mStruct, &tests.ArrayModel{ID: uuid.New()}, &tests.ArrayModel{ID: uuid.New()})
+		_, ok, err := p.buildBulkUpdateQuery(s, mapping.FieldSet{mStruct.MustFieldByName("SliceInt")}, s.Models)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("Keywords", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.Model{})
+		require.NoError(t, err)
+
+		p := testingRepository(c)
+		p.keywords = map[string]migrate.KeyWordType{"int": migrate.KWUnreservedC}
+		s := query.NewScope(mStruct, &tests.Model{ID: 1, Int: 5}, &tests.Model{ID: 2, Int: 10})
+		UpdateWith(s, UpdateExpression{Field: mStruct.MustFieldByName("Int"), Operator: UpdateIncrement})
+		q, ok, err := p.buildBulkUpdateQuery(s, mapping.FieldSet{mStruct.MustFieldByName("Int")}, s.Models)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		assert.Equal(t, `UPDATE public.models AS t SET "int" = t."int" + v."int", updated_at = now() FROM unnest($1::integer[], $2::integer[]) AS v(id, "int") WHERE t.id = v.id AND t.deleted_at IS NULL RETURNING t.id, t.updated_at, t."int"`, q.query)
+	})
+
+	t.Run("DuplicatedPrimaryKeys", func(t *testing.T) {
+		mStruct, err := c.ModelStruct(&tests.VersionedModel{})
+		require.NoError(t, err)
+
+		s := query.NewScope(mStruct, &tests.VersionedModel{ID: 1, Name: "Name", Version: 2}, &tests.VersionedModel{ID: 1, Name: "Surname", Version: 2})
+		_, _, err = p.buildBulkUpdateQuery(s, mapping.FieldSet{mStruct.MustFieldByName("Name")}, s.Models)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, query.ErrInvalidModels))
+	})
 }
@@ -0,0 +1,347 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/log"
+)
+
+// Conflict defines the conflict target and the conflict action of the Upsert query.
+type Conflict struct {
+	// Fields are the conflict target fields. These must be the primary key, a unique field or all the fields
+	// of the model's unique index. If neither Fields nor Index is set, the primary key is the conflict target.
+	Fields []*mapping.StructField
+	// Index is the name of the model's unique index used as the conflict target.
+	Index string
+	// DoNothing leaves the conflicting rows unchanged.
+	DoNothing bool
+	// Update is the fieldset updated with the inserted values on conflict. By default all the inserted fields
+	// except the conflict target and the CreatedAt field are updated.
+	Update mapping.FieldSet
+}
+
+// UpsertResult is the result of the Upsert query for a single model.
+type UpsertResult int
+
+const (
+	// UpsertSkipped is the result of the conflicting model with the DoNothing conflict action.
+	UpsertSkipped UpsertResult = iota
+	// UpsertInserted is the result of the model inserted as a new row.
+	UpsertInserted
+	// UpsertUpdated is the result of the model that updated the conflicting row.
+	UpsertUpdated
+)
+
+// String implements fmt.Stringer interface.
+func (u UpsertResult) String() string {
+	switch u {
+	case UpsertSkipped:
+		return "skipped"
+	case UpsertInserted:
+		return "inserted"
+	case UpsertUpdated:
+		return "updated"
+	default:
+		return "unknown"
+	}
+}
+
+// Upsert inserts the scope's models or resolves their conflicts with the existing rows. The conflict target and
+// action are defined by the OnConflict scope option. By default the conflicts on the primary key are resolved
+// by updating the inserted fields. The result for each model could be obtained using the UpsertResults function.
+// Implements repository.Upserter interface.
+func (p *Postgres) Upsert(ctx context.Context, s *query.Scope) error {
+	if len(s.Models) == 0 {
+		return errors.Wrap(query.ErrNoModels, "no models to upsert")
+	}
+	conflict := upsertConflict(s)
+	target, err := conflictTarget(s.ModelStruct, conflict)
+	if err != nil {
+		return err
+	}
+
+	b := &pgx.Batch{}
+	queries, err := p.parseUpsertQueries(s, b, conflict, target)
+	if err != nil {
+		log.Debug2f("parsing upsert query failed: %v", err)
+		return err
+	}
+
+	results := make([]UpsertResult, len(s.Models))
+	br := p.connection(s).SendBatch(ctx, b)
+	defer br.Close()
+	for _, q := range queries {
+		for _, index := range q.indices {
+			if results[index], err = scanUpsertRow(br.QueryRow(), s.Models[index], q.returning); err != nil {
+				log.Debugf("Upsert query failed: %v", err)
+				return errors.WrapDetf(p.neuronError(err), "upsert failed: %v", err)
+			}
+		}
+	}
+	s.StoreSet(internal.UpsertResultsKey, results)
+	return nil
+}
+
+// parseUpsertQueries queues the upsert query for each scope's model in the 'batch'. The models with the same
+// fieldset share the query and the returning fields.
+func (p *Postgres) parseUpsertQueries(s *query.Scope, batch internal.Batch, conflict *Conflict, target []*mapping.StructField) ([]*bulkInsertQuery, error) {
+	if len(s.FieldSets) != 1 && len(s.FieldSets) != len(s.Models) {
+		return nil, errors.WrapDetf(query.ErrInvalidFieldSet, "upsert requires a common fieldset or a fieldset per model")
+	}
+	bulk := &mapping.BulkFieldSet{}
+	for i := range s.Models {
+		if len(s.FieldSets) == 1 {
+			bulk.Add(s.FieldSets[0], i)
+		} else {
+			bulk.Add(s.FieldSets[i], i)
+		}
+	}
+
+	mStruct := s.ModelStruct
+	clockValue := p.clockValue()
+	insertDefaults := p.isInsertDefaults(s)
+	sb := &strings.Builder{}
+	queries := make([]*bulkInsertQuery, len(bulk.FieldSets))
+	for i, fieldSet := range bulk.FieldSets {
+		fields := &insertFields{clockValue: clockValue, defaults: insertDefaults}
+		fields.fieldSet, fields.autoSelected = p.prepareInsertFieldset(mStruct, fieldSet, insertDefaults)
+
+		var primarySelected bool
+		insert := &strings.Builder{}
+		insert.WriteString("INSERT INTO ")
+		p.writeQuotedWord(insert, mStruct.DatabaseSchemaName)
+		insert.WriteRune('.')
+		p.writeQuotedWord(insert, mStruct.DatabaseName)
+		if len(fields.fieldSet) > 0 {
+			insert.WriteString(" (")
+			for j, field := range fields.fieldSet {
+				if field.Kind() == mapping.KindPrimary {
+					primarySelected = true
+				}
+				p.writeQuotedWord(insert, field.DatabaseName)
+				if j != len(fields.fieldSet)-1 {
+					insert.WriteRune(',')
+				}
+			}
+			insert.WriteRune(')')
+		}
+		insert.WriteString(" VALUES (")
+
+		suffix := &strings.Builder{}
+		suffix.WriteRune(')')
+		if err := p.writeConflictClause(s, suffix, conflict, target, fields.fieldSet); err != nil {
+			return nil, err
+		}
+		returning := insertReturning(mStruct, fields.fieldSet, primarySelected, insertDefaults)
+		p.writeReturning(suffix, returning)
+		if len(returning) == 0 {
+			suffix.WriteString(" RETURNING ")
+		} else {
+			suffix.WriteString(", ")
+		}
+		// The xmax system column is zero only for the newly inserted rows.
+		suffix.WriteString("xmax = 0")
+
+		indices := bulk.GetIndicesByFieldset(fieldSet)
+		for _, index := range indices {
+			var (
+				values []interface{}
+				err    error
+			)
+			sb.WriteString(insert.String())
+			if len(fields.fieldSet) > 0 {
+				if values, err = p.writeInsertValues(s, sb, fields, s.Models[index], values); err != nil {
+					return nil, err
+				}
+			} else {
+				sb.WriteString("DEFAULT")
+			}
+			sb.WriteString(suffix.String())
+			batch.Queue(sb.String(), values...)
+			sb.Reset()
+			internal.ResetIncrementor(s)
+		}
+		queries[i] = &bulkInsertQuery{indices: indices, returning: returning}
+	}
+	return queries, nil
+}
+
+// writeConflictClause writes the ON CONFLICT clause for the 'target' fields and the 'conflict' action.
+// If there is nothing to update the conflicting rows are left unchanged.
+func (p *Postgres) writeConflictClause(s *query.Scope, sb *strings.Builder, conflict *Conflict, target []*mapping.StructField, fieldSet mapping.FieldSet) error {
+	sb.WriteString(" ON CONFLICT (")
+	for i, field := range target {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		p.writeQuotedWord(sb, field.DatabaseName)
+	}
+	sb.WriteRune(')')
+
+	var updateFields mapping.FieldSet
+	if !conflict.DoNothing {
+		var err error
+		if updateFields, err = conflictUpdateFields(s, conflict, target, fieldSet); err != nil {
+			return err
+		}
+	}
+	if len(updateFields) == 0 {
+		sb.WriteString(" DO NOTHING")
+		return nil
+	}
+	sb.WriteString(" DO UPDATE SET ")
+	for i, field := range updateFields {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		p.writeQuotedWord(sb, field.DatabaseName)
+		sb.WriteString(" = EXCLUDED.")
+		p.writeQuotedWord(sb, field.DatabaseName)
+	}
+	return nil
+}
+
+// conflictUpdateFields gets the fields updated on conflict. All of these fields must be inserted, so that
+// their values could be taken from the EXCLUDED row.
+func conflictUpdateFields(s *query.Scope, conflict *Conflict, target []*mapping.StructField, fieldSet mapping.FieldSet) (updateFields mapping.FieldSet, err error) {
+	if len(conflict.Update) == 0 {
+		for _, field := range fieldSet {
+			if field.Kind() == mapping.KindPrimary || field.IsCreatedAt() || containsField(target, field) {
+				continue
+			}
+			updateFields = append(updateFields, field)
+		}
+		return updateFields, nil
+	}
+
+	for _, field := range conflict.Update {
+		if field.DatabaseSkip() || field.Kind() == mapping.KindPrimary {
+			continue
+		}
+		if !fieldSet.Contains(field) {
+			return nil, errors.WrapDetf(query.ErrInvalidFieldSet, "conflict update field: '%s' is not inserted", field)
+		}
+		updateFields = append(updateFields, field)
+	}
+	// The UpdatedAt timestamp is set automatically just as for the update query.
+	if updatedAt, ok := autoUpdatedAt(s, updateFields); ok && fieldSet.Contains(updatedAt) {
+		updateFields = append(updateFields, updatedAt)
+	}
+	return updateFields, nil
+}
+
+// conflictTarget gets the upsert conflict target fields. The target must be the primary key, a unique field or
+// the fields of the model's unique index.
+func conflictTarget(mStruct *mapping.ModelStruct, conflict *Conflict) ([]*mapping.StructField, error) {
+	if conflict.Index != "" {
+		if len(conflict.Fields) != 0 {
+			return nil, errors.WrapDet(query.ErrInvalidParameter, "conflict target defined by both the fields and the index")
+		}
+		for _, index := range uniqueIndexes(mStruct) {
+			if index.Name == conflict.Index {
+				return index.Fields, nil
+			}
+		}
+		return nil, errors.WrapDetf(query.ErrInvalidParameter, "model: '%s' has no unique index: '%s'", mStruct, conflict.Index)
+	}
+
+	for _, field := range conflict.Fields {
+		if field.ModelStruct() != mStruct || field.DatabaseSkip() {
+			return nil, errors.WrapDetf(query.ErrInvalidField, "invalid conflict target field: '%s'", field)
+		}
+	}
+	switch len(conflict.Fields) {
+	case 0:
+		return []*mapping.StructField{mStruct.Primary()}, nil
+	case 1:
+		if field := conflict.Fields[0]; field.Kind() == mapping.KindPrimary || field.DatabaseUnique() {
+			return conflict.Fields, nil
+		}
+	}
+	for _, index := range uniqueIndexes(mStruct) {
+		if len(index.Fields) != len(conflict.Fields) {
+			continue
+		}
+		matched := true
+		for _, field := range conflict.Fields {
+			if !containsField(index.Fields, field) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return conflict.Fields, nil
+		}
+	}
+	return nil, errors.WrapDetf(query.ErrInvalidField, "conflict target fields of the model: '%s' are neither the primary key, a unique field nor a unique index", mStruct)
+}
+
+// uniqueIndexes gets the unique indexes defined for the model and its fields.
+func uniqueIndexes(mStruct *mapping.ModelStruct) (indexes []*mapping.DatabaseIndex) {
+	for _, index := range mStruct.DatabaseIndexes() {
+		if index.Unique {
+			indexes = append(indexes, index)
+		}
+	}
+	for _, field := range mStruct.Fields() {
+		for _, index := range field.DatabaseIndexes() {
+			if !index.Unique {
+				continue
+			}
+			var found bool
+			for _, other := range indexes {
+				if other == index {
+					found = true
+					break
+				}
+			}
+			if !found {
+				indexes = append(indexes, index)
+			}
+		}
+	}
+	return indexes
+}
+
+// scanUpsertRow scans the 'returning' fields values of the upserted row into given 'model' and checks if
+// the row was inserted or updated. If no row was returned the model was skipped.
+func scanUpsertRow(row pgx.Row, model mapping.Model, returning []*mapping.StructField) (UpsertResult, error) {
+	scanner, err := newModelFieldsScanner(model, returning)
+	if err != nil {
+		return UpsertSkipped, err
+	}
+	var inserted bool
+	if err = row.Scan(append(scanner.values, &inserted)...); err != nil {
+		if err == pgx.ErrNoRows {
+			return UpsertSkipped, nil
+		}
+		return UpsertSkipped, err
+	}
+	if err = scanner.setTimePointers(); err != nil {
+		return UpsertSkipped, err
+	}
+	if inserted {
+		return UpsertInserted, nil
+	}
+	return UpsertUpdated, nil
+}
+
+func upsertConflict(s *query.Scope) *Conflict {
+	v, ok := s.StoreGet(internal.ConflictKey)
+	if !ok {
+		return &Conflict{}
+	}
+	conflict, ok := v.(Conflict)
+	if !ok {
+		return &Conflict{}
+	}
+	return &conflict
+}
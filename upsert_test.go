@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/internal"
+	"github.com/neuronlabs/neuron-extensions/repository/postgres/tests"
+	"github.com/neuronlabs/neuron/errors"
+	"github.com/neuronlabs/neuron/mapping"
+	"github.com/neuronlabs/neuron/query"
+)
+
+func TestParseUpsertQueries(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+	p := testingRepository(c)
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	upsert := func(t *testing.T, s *query.Scope) *internal.DummyBatch {
+		t.Helper()
+		conflict := upsertConflict(s)
+		target, err := conflictTarget(s.ModelStruct, conflict)
+		require.NoError(t, err)
+
+		batch := &internal.DummyBatch{}
+		_, err = p.parseUpsertQueries(s, batch, conflict, target)
+		require.NoError(t, err)
+		return batch
+	}
+
+	t.Run("DoUpdate", func(t *testing.T) {
+		s := query.NewScope(mStruct, &tests.Model{ID: 1, AttrString: "first"}, &tests.Model{ID: 2, AttrString: "second"})
+		s.FieldSets = []mapping.FieldSet{{mStruct.Primary(), mStruct.MustFieldByName("AttrString")}}
+
+		batch := upsert(t, s)
+		require.Equal(t, 2, batch.Len())
+		for i, q := range batch.Queries {
+			assert.Equal(t, "INSERT INTO public.models (id,attr_string,int,created_at,updated_at) VALUES ($1,$2,$3,now(),now()) ON CONFLICT (id) DO UPDATE SET attr_string = EXCLUDED.attr_string, int = EXCLUDED.int, updated_at = EXCLUDED.updated_at RETURNING created_at, updated_at, xmax = 0", q.Query)
+			assert.Len(t, q.Arguments, 3)
+			assert.Equal(t, i+1, q.Arguments[0])
+		}
+	})
+
+	t.Run("UpdateFieldSet", func(t *testing.T) {
+		s := query.NewScope(mStruct, &tests.Model{ID: 1, AttrString: "first"})
+		s.FieldSets = []mapping.FieldSet{{mStruct.Primary(), mStruct.MustFieldByName("AttrString")}}
+		OnConflict(s, Conflict{Update: mapping.FieldSet{mStruct.MustFieldByName("AttrString")}})
+
+		batch := upsert(t, s)
+		require.Equal(t, 1, batch.Len())
+		assert.Equal(t, "INSERT INTO public.models (id,attr_string,int,created_at,updated_at) VALUES ($1,$2,$3,now(),now()) ON CONFLICT (id) DO UPDATE SET attr_string = EXCLUDED.attr_string, updated_at = EXCLUDED.updated_at RETURNING created_at, updated_at, xmax = 0", batch.Queries[0].Query)
+	})
+
+	t.Run("DoNothing", func(t *testing.T) {
+		s := query.NewScope(mStruct, &tests.Model{AttrString: "first"})
+		s.FieldSets = []mapping.FieldSet{{mStruct.MustFieldByName("AttrString")}}
+		OnConflict(s, Conflict{DoNothing: true})
+
+		batch := upsert(t, s)
+		require.Equal(t, 1, batch.Len())
+		assert.Equal(t, "INSERT INTO public.models (attr_string,int,created_at,updated_at) VALUES ($1,$2,now(),now()) ON CONFLICT (id) DO NOTHING RETURNING id, created_at, updated_at, xmax = 0", batch.Queries[0].Query)
+	})
+
+	t.Run("NotInsertedUpdateField", func(t *testing.T) {
+		s := query.NewScope(mStruct, &tests.Model{ID: 1})
+		s.FieldSets = []mapping.FieldSet{{mStruct.Primary()}}
+		OnConflict(s, Conflict{Update: mapping.FieldSet{mStruct.MustFieldByName("StringPtr")}})
+
+		conflict := upsertConflict(s)
+		target, err := conflictTarget(s.ModelStruct, conflict)
+		require.NoError(t, err)
+		_, err = p.parseUpsertQueries(s, &internal.DummyBatch{}, conflict, target)
+		assert.True(t, errors.Is(err, query.ErrInvalidFieldSet))
+	})
+}
+
+func TestConflictTarget(t *testing.T) {
+	c := testingController(t, false, &tests.Model{})
+
+	mStruct, err := c.ModelStruct(&tests.Model{})
+	require.NoError(t, err)
+
+	target, err := conflictTarget(mStruct, &Conflict{})
+	require.NoError(t, err)
+	assert.Equal(t, []*mapping.StructField{mStruct.Primary()}, target)
+
+	_, err = conflictTarget(mStruct, &Conflict{Fields: []*mapping.StructField{mStruct.MustFieldByName("AttrString")}})
+	assert.True(t, errors.Is(err, query.ErrInvalidField))
+
+	_, err = conflictTarget(mStruct, &Conflict{Index: "unknown"})
+	assert.True(t, errors.Is(err, query.ErrInvalidParameter))
+}